@@ -0,0 +1,141 @@
+package kzgsdk
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ErrInvalidRange is returned when an aggregation range is empty or outside of the commitments.
+var ErrInvalidRange = errors.New("invalid aggregation range")
+
+// The ChallengeContract aggregates the commitments of a namespace over inclusive ranges of
+// absolute nameSpaceCommitments indices:
+//
+//	CM_{s,j} = ∑_{i=s}^{j} r_i·cm_i,  r_i = keccak(abi.encode(r, i))
+//
+// aggregateCommitment(s) is CM_{s,s}, and verifyAggregateCommitment checks the recurrence
+// CM_{s,j+1} = CM_{s,j} + r_{j+1}·cm_{j+1} on the last step of the bisection.
+// The functions below use the same indices, so the values they return can be submitted as is.
+
+// Coefficients returns r_start, ..., r_end.
+func (s FoldSeed) Coefficients(start uint64, end uint64) []fr.Element {
+	if start > end {
+		return nil
+	}
+	coefficients := make([]fr.Element, 0, end-start+1)
+	for i := start; ; i++ {
+		coefficients = append(coefficients, s.Coefficient(i))
+		if i == end {
+			return coefficients
+		}
+	}
+}
+
+// FoldCoefficient returns the coefficient of index for gamma, see FoldSeed.Coefficient.
+func FoldCoefficient(gamma fr.Element, index uint64) fr.Element {
+	return GammaSeed(gamma).Coefficient(index)
+}
+
+// FoldCoefficients returns the coefficients of start, ..., end for gamma.
+func FoldCoefficients(gamma fr.Element, start uint64, end uint64) []fr.Element {
+	return GammaSeed(gamma).Coefficients(start, end)
+}
+
+// AggregateRange computes CM_{start,end}, commits[i] being the commitment at namespace index i.
+func AggregateRange(commits []kzg.Digest, seed FoldSeed, start uint64, end uint64) (kzg.Digest, error) {
+	if err := checkRange(start, end, len(commits)); err != nil {
+		return kzg.Digest{}, err
+	}
+	var aggregate kzg.Digest
+	_, err := aggregate.MultiExp(commits[start:end+1], seed.Coefficients(start, end), ecc.MultiExpConfig{})
+	return aggregate, err
+}
+
+// AggregateStep extends the aggregate CM_{s,index-1} with the commitment at index, returning CM_{s,index}.
+// An aggregate of the zero value is the empty sum, so AggregateStep(kzg.Digest{}, cm_s, seed, s) is CM_{s,s}.
+func AggregateStep(aggregate kzg.Digest, commit kzg.Digest, seed FoldSeed, index uint64) kzg.Digest {
+	coefficient := seed.Coefficient(index)
+	var step kzg.Digest
+	step.ScalarMultiplication(&commit, coefficient.BigInt(new(big.Int)))
+	step.Add(&step, &aggregate)
+	return step
+}
+
+// VerifyAggregateStep mirrors ChallengeContract.verifyAggregateCommitment: it reports whether
+// noConsensus = consensus + r_index·commit, commit being the commitment at index.
+func VerifyAggregateStep(consensus kzg.Digest, noConsensus kzg.Digest, commit kzg.Digest, seed FoldSeed, index uint64) bool {
+	expected := AggregateStep(consensus, commit, seed, index)
+	return expected.Equal(&noConsensus)
+}
+
+// AggregatePolynomials computes ∑_{i=start}^{end} r_i·f_i, the polynomial committed by CM_{start,end},
+// polynomials[i] being the blob at namespace index i. Only the polynomials of the range are read.
+func AggregatePolynomials(polynomials [][]fr.Element, seed FoldSeed, start uint64, end uint64) ([]fr.Element, error) {
+	if err := checkRange(start, end, len(polynomials)); err != nil {
+		return nil, err
+	}
+	return aggregatePolynomials(polynomials[start:end+1], seed, start), nil
+}
+
+// aggregatePolynomials folds polynomials, polynomials[k] being the blob at namespace index start+k.
+func aggregatePolynomials(polynomials [][]fr.Element, seed FoldSeed, start uint64) []fr.Element {
+	largestPoly := 0
+	for _, polynomial := range polynomials {
+		largestPoly = max(largestPoly, len(polynomial))
+	}
+	aggregate := make([]fr.Element, largestPoly)
+	for k, coefficient := range seed.Coefficients(start, start+uint64(len(polynomials))-1) {
+		var pj fr.Element
+		for j, c := range polynomials[k] {
+			pj.Mul(&c, &coefficient)
+			aggregate[j].Add(&aggregate[j], &pj)
+		}
+	}
+	return aggregate
+}
+
+// OpenRange computes the opening proof at openPoint of the polynomial committed by CM_{start,end},
+// the proof uploaded to ChallengeContract.uploadProof for a challenge over [start, end].
+func (sdk *DomiconSdk) OpenRange(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	seed FoldSeed,
+	start uint64,
+	end uint64,
+) (kzg.OpeningProof, error) {
+	if err := checkRange(start, end, len(polynomials)); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return sdk.OpenRangeFrom(polynomials[start:end+1], openPoint, seed, start)
+}
+
+// OpenRangeFrom is OpenRange for the blobs of the range only, polynomials[k] being the blob at
+// namespace index start+k, so the range is [start, start+len(polynomials)-1].
+func (sdk *DomiconSdk) OpenRangeFrom(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	seed FoldSeed,
+	start uint64,
+) (kzg.OpeningProof, error) {
+	if len(polynomials) == 0 {
+		return kzg.OpeningProof{}, fmt.Errorf("%w: no blob from %d", ErrInvalidRange, start)
+	}
+	aggregate := aggregatePolynomials(polynomials, seed, start)
+	if err := checkPolynomials([][]fr.Element{aggregate}, sdk.srs); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return kzg.Open(aggregate, openPoint, sdk.srs.Pk)
+}
+
+// checkRange makes sure [start, end] is a non-empty range of a slice of length n.
+func checkRange(start uint64, end uint64, n int) error {
+	if start > end || end >= uint64(n) {
+		return fmt.Errorf("%w: [%d, %d] of %d commitments", ErrInvalidRange, start, end, n)
+	}
+	return nil
+}
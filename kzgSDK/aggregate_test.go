@@ -0,0 +1,134 @@
+package kzgsdk
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldCoefficient(t *testing.T) {
+	// keccak(abi.encode(r, i)) with abi.encode of two uint256 words
+	r, _ := new(big.Int).SetString("8956114444546472096905889919082729794348506031815874064517970911421382129191", 10)
+	var gamma fr.Element
+	gamma.SetBigInt(r)
+	for _, index := range []uint64{0, 1, 2778, 1<<32 + 5} {
+		hash := crypto.Keccak256(common.BigToHash(r).Bytes(), common.BigToHash(new(big.Int).SetUint64(index)).Bytes())
+		var expected fr.Element
+		expected.SetBigInt(new(big.Int).SetBytes(hash))
+		assert.Equal(t, expected, FoldCoefficient(gamma, index))
+	}
+	assert.Equal(t, []fr.Element{FoldCoefficient(gamma, 3), FoldCoefficient(gamma, 4)}, FoldCoefficients(gamma, 3, 4))
+	assert.Nil(t, FoldCoefficients(gamma, 4, 3))
+
+	// a seed above the scalar field modulus is hashed unreduced
+	large := new(big.Int).Add(r, fr.Modulus())
+	seed, err := ChallengeSeed(large)
+	assert.NoError(t, err)
+	hash := crypto.Keccak256(common.BigToHash(large).Bytes(), common.BigToHash(big.NewInt(7)).Bytes())
+	var expected fr.Element
+	expected.SetBigInt(new(big.Int).SetBytes(hash))
+	assert.Equal(t, expected, seed.Coefficient(7))
+	assert.Equal(t, []fr.Element{seed.Coefficient(7), seed.Coefficient(8)}, seed.Coefficients(7, 8))
+}
+
+// randomSeed returns the seed of a random uint256 r, above the scalar field modulus most of the time.
+func randomSeed() FoldSeed {
+	var seed FoldSeed
+	rand.Read(seed[:])
+	return seed
+}
+
+func TestAggregateRange(t *testing.T) {
+	srs, err := kzg.NewSRS(32, big.NewInt(42))
+	assert.NoError(t, err)
+	sdk := NewDomiconSdk(srs)
+	const n = 12
+	polys := make([][]fr.Element, n)
+	commits := make([]kzg.Digest, n)
+	for i := range polys {
+		polys[i] = randomPolynomial(2 + i)
+		commits[i], err = sdk.Commit(polys[i])
+		assert.NoError(t, err)
+	}
+	var gamma fr.Element
+	gamma.SetRandom()
+	seed := GammaSeed(gamma)
+
+	tests := []struct {
+		name       string
+		start, end uint64
+	}{
+		{"single", 0, 0},
+		{"single at offset", 7, 7},
+		{"prefix", 0, 5},
+		{"middle", 3, 8},
+		{"suffix", 6, n - 1},
+		{"all", 0, n - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregate, err := AggregateRange(commits, seed, tt.start, tt.end)
+			assert.NoError(t, err)
+
+			// CM_{s,s} = r_s·cm_s and CM_{s,j+1} = CM_{s,j} + r_{j+1}·cm_{j+1}
+			recurrence := AggregateStep(kzg.Digest{}, commits[tt.start], seed, tt.start)
+			for j := tt.start; j < tt.end; j++ {
+				prefix, err := AggregateRange(commits, seed, tt.start, j)
+				assert.NoError(t, err)
+				assert.Equal(t, prefix, recurrence)
+				next, err := AggregateRange(commits, seed, tt.start, j+1)
+				assert.NoError(t, err)
+				assert.True(t, VerifyAggregateStep(prefix, next, commits[j+1], seed, j+1))
+				assert.False(t, VerifyAggregateStep(prefix, next, commits[j], seed, j+1))
+				recurrence = AggregateStep(recurrence, commits[j+1], seed, j+1)
+			}
+			assert.Equal(t, aggregate, recurrence)
+
+			// half-open FoldedCommits over the same commitments
+			folded, err := FoldedCommits(commits, gamma, uint(tt.start), uint(tt.end+1))
+			assert.NoError(t, err)
+			assert.Equal(t, aggregate, folded)
+
+			// the aggregated polynomial is committed by the aggregate and opens against it
+			poly, err := AggregatePolynomials(polys, seed, tt.start, tt.end)
+			assert.NoError(t, err)
+			polyCommit, err := sdk.Commit(poly)
+			assert.NoError(t, err)
+			assert.Equal(t, aggregate, polyCommit)
+			var point fr.Element
+			point.SetRandom()
+			proof, err := sdk.OpenRange(polys, point, seed, tt.start, tt.end)
+			assert.NoError(t, err)
+			assert.NoError(t, sdk.Verify(&aggregate, &proof, point))
+			from, err := sdk.OpenRangeFrom(polys[tt.start:tt.end+1], point, seed, tt.start)
+			assert.NoError(t, err)
+			assert.Equal(t, proof, from)
+		})
+	}
+
+	// relative indices give a different aggregate
+	shifted, err := AggregateRange(commits[3:], seed, 0, 5)
+	assert.NoError(t, err)
+	aggregate, err := AggregateRange(commits, seed, 3, 8)
+	assert.NoError(t, err)
+	assert.NotEqual(t, aggregate, shifted)
+
+	for _, r := range [][2]uint64{{5, 4}, {0, n}, {n, n}} {
+		_, err = AggregateRange(commits, seed, r[0], r[1])
+		assert.ErrorIs(t, err, ErrInvalidRange)
+		_, err = AggregatePolynomials(polys, seed, r[0], r[1])
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	}
+	_, err = sdk.OpenRangeFrom(nil, fr.Element{}, seed, 3)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = FoldedCommits(commits, gamma, 4, 4)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = FoldedCommits(commits, gamma, 4, n+1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,320 @@
+// Package blobstore keeps the blobs of the namespaces a storage node stores on disk, with the
+// commitments recorded for them on chain.
+package blobstore
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/challenge"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+)
+
+var (
+	// ErrCommitmentMismatch is returned by Put for data whose commitment is not the recorded one.
+	ErrCommitmentMismatch = errors.New("data does not match the commitment")
+	// ErrCorrupted is returned when a stored blob no longer matches its recorded commitment.
+	ErrCorrupted = errors.New("stored blob does not match its commitment")
+)
+
+// Layout of the store, each blob being keyed by nameSpaceKey || index:
+//
+//	blob     || key -> raw data
+//	elements || key -> the field elements of DataToPolynomial(data), 32 bytes each, if kept
+//	commit   || key -> X || Y of the recorded commitment
+var (
+	blobPrefix     = []byte("blob")
+	elementsPrefix = []byte("elements")
+	commitPrefix   = []byte("commit")
+)
+
+var _ challenge.IteratingSource = (*Store)(nil)
+
+// OpenLevelDB opens, or creates, the LevelDB database at path to back a Store.
+func OpenLevelDB(path string) (ethdb.KeyValueStore, error) {
+	return leveldb.New(path, 256, 64, "", false)
+}
+
+// Config configures a Store.
+type Config struct {
+	// KeepElements stores the field elements of each blob next to its data, trading disk space
+	// for the decoding of the data on each read.
+	KeepElements bool
+}
+
+// Store is a blob store keyed by (nameSpaceKey, index). Every read checks the blob against the
+// commitment recorded with it, so a corrupted disk never makes a storage node answer a challenge
+// with the wrong data. Store is a challenge.IteratingSource.
+type Store struct {
+	db     ethdb.KeyValueStore
+	sdk    *kzgsdk.DomiconSdk
+	config Config
+}
+
+// New returns a Store keeping its blobs in db, committing with sdk.
+func New(db ethdb.KeyValueStore, sdk *kzgsdk.DomiconSdk, config Config) *Store {
+	return &Store{db: db, sdk: sdk, config: config}
+}
+
+func blobKey(prefix []byte, nameSpaceKey [32]byte, index uint64) []byte {
+	key := make([]byte, 0, len(prefix)+32+8)
+	key = append(append(key, prefix...), nameSpaceKey[:]...)
+	return binary.BigEndian.AppendUint64(key, index)
+}
+
+// Put stores the data of the blob at index of the namespace with its commitment, as recorded by
+// the CommitmentManager. It returns ErrCommitmentMismatch if the data does not match it.
+func (s *Store) Put(nameSpaceKey [32]byte, index uint64, data []byte, commitment kzg.Digest) error {
+	polynomial := kzgsdk.DataToPolynomial(data)
+	commit, err := s.sdk.Commit(polynomial)
+	if err != nil {
+		return err
+	}
+	if !commit.Equal(&commitment) {
+		return fmt.Errorf("%w: index %d of namespace %x", ErrCommitmentMismatch, index, nameSpaceKey)
+	}
+	p := contracts.G1PointFromDigest(&commitment)
+	encoded := make([]byte, 64)
+	p.X.FillBytes(encoded[:32])
+	p.Y.FillBytes(encoded[32:])
+
+	batch := s.db.NewBatch()
+	if err := batch.Put(blobKey(blobPrefix, nameSpaceKey, index), data); err != nil {
+		return err
+	}
+	if err := batch.Put(blobKey(commitPrefix, nameSpaceKey, index), encoded); err != nil {
+		return err
+	}
+	if s.config.KeepElements {
+		elements := make([]byte, 0, len(polynomial)*fr.Bytes)
+		for i := range polynomial {
+			b := polynomial[i].Bytes()
+			elements = append(elements, b[:]...)
+		}
+		if err := batch.Put(blobKey(elementsPrefix, nameSpaceKey, index), elements); err != nil {
+			return err
+		}
+	} else if err := batch.Delete(blobKey(elementsPrefix, nameSpaceKey, index)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Has reports whether the blob at index of the namespace is stored.
+func (s *Store) Has(nameSpaceKey [32]byte, index uint64) (bool, error) {
+	return s.db.Has(blobKey(commitPrefix, nameSpaceKey, index))
+}
+
+// Delete removes the blob at index of the namespace.
+func (s *Store) Delete(nameSpaceKey [32]byte, index uint64) error {
+	batch := s.db.NewBatch()
+	for _, prefix := range [][]byte{blobPrefix, elementsPrefix, commitPrefix} {
+		if err := batch.Delete(blobKey(prefix, nameSpaceKey, index)); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// Commitment returns the commitment recorded for the blob at index of the namespace, or
+// challenge.ErrUnknownData.
+func (s *Store) Commitment(nameSpaceKey [32]byte, index uint64) (kzg.Digest, error) {
+	value, err := s.get(commitPrefix, nameSpaceKey, index)
+	if err != nil {
+		return kzg.Digest{}, err
+	}
+	if len(value) != 64 {
+		return kzg.Digest{}, fmt.Errorf("%w: commitment of index %d of namespace %x", ErrCorrupted, index, nameSpaceKey)
+	}
+	return contracts.G1Point{X: new(big.Int).SetBytes(value[:32]), Y: new(big.Int).SetBytes(value[32:])}.ToDigest()
+}
+
+// Data returns the data of the blob at index of the namespace, after checking it against its
+// commitment.
+func (s *Store) Data(nameSpaceKey [32]byte, index uint64) ([]byte, error) {
+	data, _, _, err := s.read(nameSpaceKey, index, false)
+	return data, err
+}
+
+// Polynomial returns the blob at index of the namespace as a polynomial, after checking it
+// against its commitment.
+func (s *Store) Polynomial(nameSpaceKey [32]byte, index uint64) ([]fr.Element, error) {
+	_, polynomial, _, err := s.read(nameSpaceKey, index, true)
+	return polynomial, err
+}
+
+// read returns the checked blob and its commitment, and its data unless polynomialOnly is set
+// and the elements are kept.
+func (s *Store) read(nameSpaceKey [32]byte, index uint64, polynomialOnly bool) ([]byte, []fr.Element, kzg.Digest, error) {
+	commitment, err := s.Commitment(nameSpaceKey, index)
+	if err != nil {
+		return nil, nil, kzg.Digest{}, err
+	}
+	var (
+		data       []byte
+		polynomial []fr.Element
+	)
+	if polynomialOnly {
+		polynomial, err = s.elements(nameSpaceKey, index)
+		if err != nil {
+			return nil, nil, kzg.Digest{}, err
+		}
+	}
+	if polynomial == nil {
+		if data, err = s.get(blobPrefix, nameSpaceKey, index); err != nil {
+			return nil, nil, kzg.Digest{}, err
+		}
+		polynomial = kzgsdk.DataToPolynomial(data)
+	}
+	commit, err := s.sdk.Commit(polynomial)
+	if err != nil {
+		return nil, nil, kzg.Digest{}, err
+	}
+	if !commit.Equal(&commitment) {
+		return nil, nil, kzg.Digest{}, fmt.Errorf("%w: index %d of namespace %x", ErrCorrupted, index, nameSpaceKey)
+	}
+	return data, polynomial, commitment, nil
+}
+
+// elements returns the kept field elements of a blob, nil if they are not kept.
+func (s *Store) elements(nameSpaceKey [32]byte, index uint64) ([]fr.Element, error) {
+	key := blobKey(elementsPrefix, nameSpaceKey, index)
+	if has, err := s.db.Has(key); err != nil || !has {
+		return nil, err
+	}
+	value, err := s.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 || len(value)%fr.Bytes != 0 {
+		return nil, fmt.Errorf("%w: elements of index %d of namespace %x", ErrCorrupted, index, nameSpaceKey)
+	}
+	polynomial := make([]fr.Element, len(value)/fr.Bytes)
+	for i := range polynomial {
+		if err := polynomial[i].SetBytesCanonical(value[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, fmt.Errorf("%w: elements of index %d of namespace %x: %v", ErrCorrupted, index, nameSpaceKey, err)
+		}
+	}
+	return polynomial, nil
+}
+
+func (s *Store) get(prefix []byte, nameSpaceKey [32]byte, index uint64) ([]byte, error) {
+	key := blobKey(prefix, nameSpaceKey, index)
+	has, err := s.db.Has(key)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, fmt.Errorf("%w: index %d of namespace %x", challenge.ErrUnknownData, index, nameSpaceKey)
+	}
+	return s.db.Get(key)
+}
+
+// Commitments returns the recorded commitments of the inclusive range [start, end].
+func (s *Store) Commitments(ctx context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([]kzg.Digest, error) {
+	if start > end {
+		return nil, fmt.Errorf("invalid range [%d, %d]", start, end)
+	}
+	commits := make([]kzg.Digest, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		commit, err := s.Commitment(nameSpaceKey, i)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// Polynomials returns the checked polynomials of the inclusive range [start, end], all in memory;
+// Iterate reads them one at a time.
+func (s *Store) Polynomials(ctx context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([][]fr.Element, error) {
+	if start > end {
+		return nil, fmt.Errorf("invalid range [%d, %d]", start, end)
+	}
+	polynomials := make([][]fr.Element, 0, end-start+1)
+	it := s.Iterate(nameSpaceKey, start, end)
+	for it.Next(ctx) {
+		polynomials = append(polynomials, it.Polynomial())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return polynomials, nil
+}
+
+// Iterator reads the blobs of a range one at a time, so that only one of them is in memory.
+type Iterator struct {
+	store        *Store
+	nameSpaceKey [32]byte
+	next, end    uint64
+	done         bool
+
+	index      uint64
+	polynomial []fr.Element
+	commitment kzg.Digest
+	err        error
+}
+
+// Iterate returns an Iterator over the blobs of the inclusive range [start, end] of the namespace.
+func (s *Store) Iterate(nameSpaceKey [32]byte, start uint64, end uint64) *Iterator {
+	return &Iterator{store: s, nameSpaceKey: nameSpaceKey, next: start, end: end, done: start > end}
+}
+
+// IteratePolynomials returns Iterate(nameSpaceKey, start, end), for the folding of the SDK.
+func (s *Store) IteratePolynomials(nameSpaceKey [32]byte, start uint64, end uint64) kzgsdk.PolynomialIterator {
+	return s.Iterate(nameSpaceKey, start, end)
+}
+
+// Next reads and checks the next blob, reporting whether there is one. It returns false at the
+// end of the range or on the first error, see Err.
+func (it *Iterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	if it.err = ctx.Err(); it.err != nil {
+		it.done = true
+		return false
+	}
+	_, it.polynomial, it.commitment, it.err = it.store.read(it.nameSpaceKey, it.next, true)
+	if it.err != nil {
+		it.done = true
+		it.polynomial = nil
+		return false
+	}
+	it.index = it.next
+	it.done = it.next == it.end
+	it.next++
+	return true
+}
+
+// Index returns the namespace index of the current blob.
+func (it *Iterator) Index() uint64 {
+	return it.index
+}
+
+// Polynomial returns the current blob as a polynomial.
+func (it *Iterator) Polynomial() []fr.Element {
+	return it.polynomial
+}
+
+// Commitment returns the recorded commitment of the current blob.
+func (it *Iterator) Commitment() kzg.Digest {
+	return it.commitment
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
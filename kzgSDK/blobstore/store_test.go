@@ -0,0 +1,193 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/challenge"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var nameSpaceKey = [32]byte{0xa}
+
+func newTestSdk(t *testing.T) *kzgsdk.DomiconSdk {
+	sdk, err := kzgsdk.NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	return sdk
+}
+
+// putBlobs stores n random blobs at indices 0 to n-1 and returns their data and commitments.
+func putBlobs(t *testing.T, s *Store, sdk *kzgsdk.DomiconSdk, n int) ([][]byte, []kzg.Digest) {
+	datas := make([][]byte, n)
+	commits := make([]kzg.Digest, n)
+	for i := range datas {
+		datas[i] = make([]byte, 50+31*i)
+		_, err := rand.Read(datas[i])
+		require.NoError(t, err)
+		commits[i], err = sdk.CommitData(datas[i])
+		require.NoError(t, err)
+		require.NoError(t, s.Put(nameSpaceKey, uint64(i), datas[i], commits[i]))
+	}
+	return datas, commits
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	for _, config := range []Config{{}, {KeepElements: true}} {
+		db := memorydb.New()
+		s := New(db, sdk, config)
+		datas, commits := putBlobs(t, s, sdk, 5)
+
+		for i := range datas {
+			data, err := s.Data(nameSpaceKey, uint64(i))
+			require.NoError(t, err)
+			assert.Equal(t, datas[i], data)
+			polynomial, err := s.Polynomial(nameSpaceKey, uint64(i))
+			require.NoError(t, err)
+			assert.Equal(t, kzgsdk.DataToPolynomial(datas[i]), polynomial)
+			commit, err := s.Commitment(nameSpaceKey, uint64(i))
+			require.NoError(t, err)
+			assert.Equal(t, commits[i], commit)
+		}
+		has, err := db.Has(blobKey(elementsPrefix, nameSpaceKey, 0))
+		require.NoError(t, err)
+		assert.Equal(t, config.KeepElements, has)
+
+		got, err := s.Commitments(ctx, nameSpaceKey, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, commits[1:4], got)
+		polynomials, err := s.Polynomials(ctx, nameSpaceKey, 0, 4)
+		require.NoError(t, err)
+		assert.Len(t, polynomials, 5)
+
+		// a blob is only stored with its own commitment
+		assert.ErrorIs(t, s.Put(nameSpaceKey, 5, datas[0], commits[1]), ErrCommitmentMismatch)
+		_, err = s.Data(nameSpaceKey, 5)
+		assert.ErrorIs(t, err, challenge.ErrUnknownData)
+		_, err = s.Polynomial([32]byte{0xb}, 0)
+		assert.ErrorIs(t, err, challenge.ErrUnknownData)
+
+		require.NoError(t, s.Delete(nameSpaceKey, 4))
+		has, err = s.Has(nameSpaceKey, 4)
+		require.NoError(t, err)
+		assert.False(t, has)
+		_, err = s.Commitments(ctx, nameSpaceKey, 3, 4)
+		assert.ErrorIs(t, err, challenge.ErrUnknownData)
+	}
+}
+
+func TestStoreCorruption(t *testing.T) {
+	sdk := newTestSdk(t)
+	db := memorydb.New()
+	s := New(db, sdk, Config{KeepElements: true})
+	datas, _ := putBlobs(t, s, sdk, 3)
+
+	// a flipped bit of the data, read through the data
+	corrupted := append([]byte(nil), datas[0]...)
+	corrupted[7] ^= 1
+	require.NoError(t, db.Put(blobKey(blobPrefix, nameSpaceKey, 0), corrupted))
+	_, err := s.Data(nameSpaceKey, 0)
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	// a flipped bit of the elements, read through the elements
+	elements, err := db.Get(blobKey(elementsPrefix, nameSpaceKey, 1))
+	require.NoError(t, err)
+	elements[40] ^= 1
+	require.NoError(t, db.Put(blobKey(elementsPrefix, nameSpaceKey, 1), elements))
+	_, err = s.Polynomial(nameSpaceKey, 1)
+	assert.ErrorIs(t, err, ErrCorrupted)
+	_, err = s.Data(nameSpaceKey, 1)
+	assert.NoError(t, err)
+
+	// elements that are not field elements
+	elements[0] = 0xff
+	require.NoError(t, db.Put(blobKey(elementsPrefix, nameSpaceKey, 1), elements))
+	_, err = s.Polynomial(nameSpaceKey, 1)
+	assert.ErrorIs(t, err, ErrCorrupted)
+	require.NoError(t, db.Put(blobKey(elementsPrefix, nameSpaceKey, 1), elements[:33]))
+	_, err = s.Polynomial(nameSpaceKey, 1)
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	require.NoError(t, db.Put(blobKey(commitPrefix, nameSpaceKey, 2), []byte{1}))
+	_, err = s.Commitment(nameSpaceKey, 2)
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestStoreIterator(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	s := New(memorydb.New(), sdk, Config{})
+	datas, commits := putBlobs(t, s, sdk, 6)
+
+	it := s.Iterate(nameSpaceKey, 2, 5)
+	index := uint64(2)
+	for it.Next(ctx) {
+		assert.Equal(t, index, it.Index())
+		assert.Equal(t, kzgsdk.DataToPolynomial(datas[index]), it.Polynomial())
+		assert.Equal(t, commits[index], it.Commitment())
+		index++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, uint64(6), index)
+	assert.False(t, it.Next(ctx))
+
+	// a missing blob stops the iteration
+	require.NoError(t, s.Delete(nameSpaceKey, 3))
+	it = s.Iterate(nameSpaceKey, 2, 5)
+	assert.True(t, it.Next(ctx))
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), challenge.ErrUnknownData)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	it = s.Iterate(nameSpaceKey, 0, 1)
+	assert.False(t, it.Next(cancelled))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+
+	it = s.Iterate(nameSpaceKey, 1, 0)
+	assert.False(t, it.Next(ctx))
+	assert.NoError(t, it.Err())
+}
+
+func TestStorePersistence(t *testing.T) {
+	path := t.TempDir()
+	sdk := newTestSdk(t)
+	db, err := OpenLevelDB(path)
+	require.NoError(t, err)
+	datas, commits := putBlobs(t, New(db, sdk, Config{KeepElements: true}), sdk, 3)
+	require.NoError(t, db.Close())
+
+	db, err = OpenLevelDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+	s := New(db, sdk, Config{})
+	for i := range datas {
+		data, err := s.Data(nameSpaceKey, uint64(i))
+		require.NoError(t, err)
+		assert.Equal(t, datas[i], data)
+	}
+
+	// the store answers challenges as a challenge.Source
+	polynomials, err := s.Polynomials(context.Background(), nameSpaceKey, 0, 2)
+	require.NoError(t, err)
+	seed := kzgsdk.FoldSeed{3}
+	var point fr.Element
+	point.SetUint64(4)
+	aggregate, err := kzgsdk.AggregateRange(commits, seed, 0, 2)
+	require.NoError(t, err)
+	proof, err := sdk.OpenRange(polynomials, point, seed, 0, 2)
+	require.NoError(t, err)
+	assert.NoError(t, kzg.Verify(&aggregate, &proof, point, sdk.SRS().Vk))
+
+	// and folds the blobs as they are read
+	streamed, err := sdk.OpenIterator(context.Background(), s.IteratePolynomials(nameSpaceKey, 0, 2), point, seed, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, proof, streamed)
+}
@@ -0,0 +1,285 @@
+package challenge
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ChallengerConfig configures a Challenger.
+type ChallengerConfig struct {
+	// PollInterval is the interval at which Run and Wait poll the challenges, DefaultPollInterval if zero.
+	PollInterval time.Duration
+	// PrefixInterval is the checkpoint interval of the prefix aggregates, √n if zero.
+	PrefixInterval uint64
+	// Rand is the source of r and point, crypto/rand if nil.
+	Rand io.Reader
+}
+
+// Challenger is the user side of the ChallengeContract. It holds the commitments of a namespace,
+// challenges storage nodes over ranges of them and plays the bisection: each aggregate submitted
+// by the storage node is compared with the one computed locally, and agreed with if they match.
+type Challenger struct {
+	contract     Contract
+	nameSpaceKey [32]byte
+	config       ChallengerConfig
+
+	stepMu   sync.Mutex
+	mu       sync.Mutex
+	commits  []kzg.Digest
+	tracked  map[uint64]*kzgsdk.PrefixAggregates
+	outcomes map[uint64]Outcome
+}
+
+// NewChallenger returns a Challenger of the namespace nameSpaceKey sending its transactions with
+// contract, commits[i] being the commitment at namespace index i.
+func NewChallenger(contract Contract, nameSpaceKey [32]byte, commits []kzg.Digest, config ChallengerConfig) *Challenger {
+	if config.PollInterval == 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	return &Challenger{
+		contract:     contract,
+		nameSpaceKey: nameSpaceKey,
+		config:       config,
+		commits:      append([]kzg.Digest(nil), commits...),
+		tracked:      make(map[uint64]*kzgsdk.PrefixAggregates),
+		outcomes:     make(map[uint64]Outcome),
+	}
+}
+
+// AddCommitment appends the commitment of a new blob of the namespace and returns its index.
+func (c *Challenger) AddCommitment(commit kzg.Digest) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commits = append(c.commits, commit)
+	return uint64(len(c.commits) - 1)
+}
+
+// RandomChallengeElements draws r and point uniformly below the scalar field modulus, the range
+// in which Verifier.verify accepts the point. rand.Int rejects the draws above the modulus rather
+// than reducing them, so the values are not biased. A nil random reads from crypto/rand.
+func RandomChallengeElements(random io.Reader) (r *big.Int, point *big.Int, err error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	if r, err = rand.Int(random, fr.Modulus()); err != nil {
+		return nil, nil, err
+	}
+	if point, err = rand.Int(random, fr.Modulus()); err != nil {
+		return nil, nil, err
+	}
+	return r, point, nil
+}
+
+// Challenge challenges storageAddr over the commitments [start, end] with a fresh r and point,
+// and follows the new challenge. It returns the id of the challenge.
+func (c *Challenger) Challenge(ctx context.Context, storageAddr common.Address, start uint64, end uint64) (uint64, error) {
+	c.mu.Lock()
+	commits := c.commits
+	c.mu.Unlock()
+	r, point, err := RandomChallengeElements(c.config.Rand)
+	if err != nil {
+		return 0, err
+	}
+	seed, _, err := kzgsdk.ChallengeElements(r, point)
+	if err != nil {
+		return 0, err
+	}
+	// the aggregates are computed first, an invalid range does not cost a transaction
+	prefix, err := kzgsdk.NewPrefixAggregates(commits, seed, start, end, c.config.PrefixInterval)
+	if err != nil {
+		return 0, err
+	}
+	id, err := c.contract.CreateChallenge(ctx, start, end, storageAddr, r, point, c.nameSpaceKey)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tracked[id] = prefix
+	return id, nil
+}
+
+// Track starts following a challenge created by the account of the Challenger, to resume the
+// challenges after a restart.
+func (c *Challenger) Track(ctx context.Context, id uint64) error {
+	c.mu.Lock()
+	_, tracked := c.tracked[id]
+	_, finished := c.outcomes[id]
+	commits := c.commits
+	c.mu.Unlock()
+	if tracked || finished {
+		return nil
+	}
+
+	challenge, err := c.contract.Challenge(ctx, id)
+	if err != nil {
+		return err
+	}
+	if challenge.Challenger != c.contract.Account() {
+		return fmt.Errorf("%w: challenge %d was created by %s", ErrNotParticipant, id, challenge.Challenger)
+	}
+	if challenge.NameSpaceKey != c.nameSpaceKey {
+		return fmt.Errorf("%w: challenge %d is over namespace %x", ErrNotParticipant, id, challenge.NameSpaceKey)
+	}
+	seed, _, err := kzgsdk.ChallengeElements(challenge.R, challenge.Point)
+	if err != nil {
+		// Verifier.verify rejects a point outside of the scalar field, the storage node cannot answer
+		c.finish(id, Outcome{Status: challenge.Status, Err: err})
+		return nil
+	}
+	prefix, err := kzgsdk.NewPrefixAggregates(commits, seed, challenge.Start.Uint64(), challenge.End.Uint64(), c.config.PrefixInterval)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tracked[id] = prefix
+	return nil
+}
+
+// Outcome returns the outcome of a challenge the Challenger stopped following.
+// CHALLENGE_SUCCESSFUL means that the storage node proved that it holds the data.
+func (c *Challenger) Outcome(id uint64) (Outcome, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	outcome, ok := c.outcomes[id]
+	return outcome, ok
+}
+
+// Pending returns the number of challenges being followed.
+func (c *Challenger) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tracked)
+}
+
+// Step acts once on every followed challenge: it submits an opinion on the aggregates waiting for
+// one, and records the outcome of the challenges that ended. Errors do not stop the other
+// challenges, the failed ones are retried on the next Step.
+func (c *Challenger) Step(ctx context.Context) error {
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+	c.mu.Lock()
+	ids := make([]uint64, 0, len(c.tracked))
+	for id := range c.tracked {
+		ids = append(ids, id)
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, id := range ids {
+		c.mu.Lock()
+		prefix := c.tracked[id]
+		c.mu.Unlock()
+		if err := c.judge(ctx, id, prefix); err != nil {
+			errs = append(errs, fmt.Errorf("challenge %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run follows the challenges until ctx is done, polling them every PollInterval.
+// The errors of a Step are logged and retried.
+func (c *Challenger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := c.Step(ctx); err != nil {
+			log.Warn("Failed to follow challenges", "err", err)
+		}
+	}
+}
+
+// Wait steps the challenges every PollInterval until the challenge of the given id ends, and
+// returns its outcome.
+func (c *Challenger) Wait(ctx context.Context, id uint64) (Outcome, error) {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.Step(ctx); err != nil {
+			log.Warn("Failed to follow challenges", "err", err)
+		}
+		if outcome, ok := c.Outcome(id); ok {
+			return outcome, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Outcome{}, ctx.Err()
+		}
+	}
+}
+
+// judge submits an opinion on the last aggregate of the challenge if it waits for one.
+func (c *Challenger) judge(ctx context.Context, id uint64, prefix *kzgsdk.PrefixAggregates) error {
+	challenge, err := c.contract.Challenge(ctx, id)
+	if err != nil {
+		return err
+	}
+	if challenge.Status.Done() {
+		c.finish(id, Outcome{Status: challenge.Status})
+		return nil
+	}
+	block, err := c.contract.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if block >= challenge.TimeoutBlock.Uint64() {
+		c.finish(id, Outcome{Status: challenge.Status, Err: ErrTimedOut})
+		return nil
+	}
+
+	var (
+		submitted contracts.G1Point
+		index     uint64
+	)
+	switch challenge.Status {
+	case contracts.StatusFirstCommitSubmitted:
+		submitted, index = challenge.AggregateCommitment, prefix.End()
+	case contracts.StatusRecommitSubmitted:
+		details, err := c.contract.ChallengeDetails(ctx, id)
+		if err != nil {
+			return err
+		}
+		submitted, index = details.CurrAggregateCommitment, details.CurrentIndex.Uint64()
+	default:
+		// waiting for the storage node
+		return nil
+	}
+	expected, err := prefix.Aggregate(index)
+	if err != nil {
+		return err
+	}
+	// an aggregate that is not a valid point cannot be the expected one
+	aggregate, err := submitted.ToDigest()
+	agreed := err == nil && aggregate.Equal(&expected)
+	return c.contract.SubmitOpinion(ctx, id, agreed)
+}
+
+// finish stops following a challenge.
+func (c *Challenger) finish(id uint64, outcome Outcome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tracked, id)
+	c.outcomes[id] = outcome
+}
@@ -0,0 +1,167 @@
+package challenge
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageMoves sets the status and submitted aggregate of a challenge as a storage node would.
+func (c *fakeContract) storageMoves(id uint64, status contracts.ChallengeStatus, aggregate contracts.G1Point, currentIndex uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.challenges[id].Status = status
+	if status == contracts.StatusFirstCommitSubmitted {
+		c.challenges[id].AggregateCommitment = aggregate
+	} else {
+		c.details[id].CurrAggregateCommitment = aggregate
+	}
+	c.details[id].CurrentIndex = new(big.Int).SetUint64(currentIndex)
+}
+
+func (c *fakeContract) submittedOpinions() []bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]bool(nil), c.opinions...)
+}
+
+func TestRandomChallengeElements(t *testing.T) {
+	r, point, err := RandomChallengeElements(bytes.NewReader(make([]byte, 16)))
+	assert.Error(t, err)
+	assert.Nil(t, r)
+	assert.Nil(t, point)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 16; i++ {
+		r, point, err = RandomChallengeElements(nil)
+		require.NoError(t, err)
+		_, _, err = kzgsdk.ChallengeElements(r, point)
+		assert.NoError(t, err)
+		assert.False(t, seen[r.String()])
+		seen[r.String()] = true
+	}
+}
+
+func TestChallengerBisection(t *testing.T) {
+	ctx := context.Background()
+	_, commits := newNameSpace(t, newTestSdk(t), 10)
+	contract := newFakeContract(challengerAddress)
+	challenger := NewChallenger(contract, nameSpaceKey, commits, ChallengerConfig{})
+
+	id, err := challenger.Challenge(ctx, storageAddress, 1, 8)
+	require.NoError(t, err)
+	challenge, err := contract.Challenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, challengerAddress, challenge.Challenger)
+	assert.Equal(t, storageAddress, challenge.StorageAddr)
+	gamma, _, err := kzgsdk.ChallengeElements(challenge.R, challenge.Point)
+	require.NoError(t, err)
+	aggregate := func(start, end uint64) contracts.G1Point {
+		d, err := kzgsdk.AggregateRange(commits, gamma, start, end)
+		require.NoError(t, err)
+		return contracts.G1PointFromDigest(&d)
+	}
+
+	// nothing to judge before the storage node submits
+	require.NoError(t, challenger.Step(ctx))
+	assert.Empty(t, contract.submittedOpinions())
+
+	for _, tt := range []struct {
+		status    contracts.ChallengeStatus
+		aggregate contracts.G1Point
+		index     uint64
+		agreed    bool
+	}{
+		{contracts.StatusFirstCommitSubmitted, aggregate(2, 8), 0, false},
+		{contracts.StatusRecommitSubmitted, aggregate(1, 4), 4, true},
+		{contracts.StatusRecommitSubmitted, aggregate(1, 6), 5, false},
+		{contracts.StatusRecommitSubmitted, contracts.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}, 5, false},
+		{contracts.StatusRecommitSubmitted, aggregate(1, 5), 5, true},
+	} {
+		contract.storageMoves(id, tt.status, tt.aggregate, tt.index)
+		require.NoError(t, challenger.Step(ctx))
+		opinions := contract.submittedOpinions()
+		assert.Equal(t, tt.agreed, opinions[len(opinions)-1], "%s at %d", tt.status, tt.index)
+	}
+	assert.Len(t, contract.submittedOpinions(), 5)
+
+	contract.storageMoves(id, contracts.StatusChallengeFailed, contracts.G1Point{}, 5)
+	require.NoError(t, challenger.Step(ctx))
+	outcome, ok := challenger.Outcome(id)
+	assert.True(t, ok)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeFailed}, outcome)
+	assert.Zero(t, challenger.Pending())
+}
+
+func TestChallengerWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, commits := newNameSpace(t, newTestSdk(t), 6)
+	contract := newFakeContract(challengerAddress)
+	challenger := NewChallenger(contract, nameSpaceKey, commits[:4], ChallengerConfig{PollInterval: 10 * time.Millisecond})
+
+	_, err := challenger.Challenge(ctx, storageAddress, 2, 4)
+	assert.ErrorIs(t, err, kzgsdk.ErrInvalidRange)
+	assert.Empty(t, contract.challenges)
+	assert.Equal(t, uint64(4), challenger.AddCommitment(commits[4]))
+
+	id, err := challenger.Challenge(ctx, storageAddress, 2, 4)
+	require.NoError(t, err)
+	challenge, err := contract.Challenge(ctx, id)
+	require.NoError(t, err)
+	gamma, _, err := kzgsdk.ChallengeElements(challenge.R, challenge.Point)
+	require.NoError(t, err)
+	honest, err := kzgsdk.AggregateRange(commits, gamma, 2, 4)
+	require.NoError(t, err)
+
+	go func() {
+		contract.storageMoves(id, contracts.StatusFirstCommitSubmitted, contracts.G1PointFromDigest(&honest), 0)
+		for {
+			if c, _ := contract.Challenge(ctx, id); c.Status == contracts.StatusAgreementReached {
+				contract.storageMoves(id, contracts.StatusChallengeSuccessful, contracts.G1Point{}, 0)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	outcome, err := challenger.Wait(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, outcome)
+	assert.Equal(t, []bool{true}, contract.submittedOpinions())
+}
+
+func TestChallengerTrack(t *testing.T) {
+	ctx := context.Background()
+	_, commits := newNameSpace(t, newTestSdk(t), 4)
+	contract := newFakeContract(challengerAddress)
+	challenger := NewChallenger(contract, nameSpaceKey, commits, ChallengerConfig{})
+
+	id, err := contract.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(5), big.NewInt(6), [32]byte{1})
+	require.NoError(t, err)
+	assert.ErrorIs(t, challenger.Track(ctx, id), ErrNotParticipant)
+
+	id, err = contract.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(5), fr.Modulus(), nameSpaceKey)
+	require.NoError(t, err)
+	assert.NoError(t, challenger.Track(ctx, id))
+	outcome, ok := challenger.Outcome(id)
+	assert.True(t, ok)
+	assert.ErrorIs(t, outcome.Err, kzgsdk.ErrPointOutOfField)
+
+	id, err = contract.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(5), big.NewInt(6), nameSpaceKey)
+	require.NoError(t, err)
+	require.NoError(t, challenger.Track(ctx, id))
+	assert.Equal(t, 1, challenger.Pending())
+	contract.block += TimeoutBlocks
+	require.NoError(t, challenger.Step(ctx))
+	outcome, ok = challenger.Outcome(id)
+	assert.True(t, ok)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeCreated, Err: ErrTimedOut}, outcome)
+}
@@ -0,0 +1,149 @@
+// Package challenge implements the off-chain parties of a ChallengeContract dispute: the storage
+// node answering challenges and the challenger driving the bisection.
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// TimeoutBlocks is the number of blocks each party has to act, see ChallengeContract.
+const TimeoutBlocks = 600
+
+var (
+	// ErrReverted is returned when a transaction to the ChallengeContract is reverted.
+	ErrReverted = errors.New("challenge transaction reverted")
+	// ErrTimedOut is reported for a challenge whose timeout block has passed.
+	ErrTimedOut = errors.New("challenge timed out")
+)
+
+// Contract is the ChallengeContract as seen by one account: transactions are sent by that account,
+// challenge ids are nonces. It is implemented by ChainContract, and by in-process stand-ins in tests.
+type Contract interface {
+	// Account returns the address transactions are sent from.
+	Account() common.Address
+	// BlockNumber returns the current block number.
+	BlockNumber(ctx context.Context) (uint64, error)
+	// Challenge returns the challenge of the given id.
+	Challenge(ctx context.Context, id uint64) (contracts.Challenge, error)
+	// ChallengeDetails returns the bisection state of the challenge of the given id.
+	ChallengeDetails(ctx context.Context, id uint64) (contracts.ChallengeDetails, error)
+
+	// CreateChallenge creates a challenge and returns its id.
+	CreateChallenge(ctx context.Context, start uint64, end uint64, storageAddr common.Address, r *big.Int, point *big.Int, nameSpaceKey [32]byte) (uint64, error)
+	// SubmitAggregateCommitment submits the aggregate commitment requested by the challenge.
+	SubmitAggregateCommitment(ctx context.Context, id uint64, commitment contracts.G1Point) error
+	// SubmitOpinion agrees or disagrees with the last aggregate commitment of the challenge.
+	SubmitOpinion(ctx context.Context, id uint64, agreed bool) error
+	// UploadProof uploads the opening proof of the aggregate commitment of the challenge.
+	UploadProof(ctx context.Context, id uint64, proof contracts.G1Point, value *big.Int) error
+
+	// WatchChallengeCreated sends the ChallengeCreated events to sink.
+	WatchChallengeCreated(ctx context.Context, sink chan<- *contracts.ChallengeCreated) (event.Subscription, error)
+}
+
+// ChainBackend is the node connection used by ChainContract, an *ethclient.Client or a simulated client.
+type ChainBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// ChainContract is a Contract backed by a deployed ChallengeContract. Transactions are signed with
+// opts and wait to be mined, a reverted transaction returns ErrReverted.
+type ChainContract struct {
+	backend  ChainBackend
+	contract *contracts.ChallengeContract
+	opts     *bind.TransactOpts
+}
+
+// NewChainContract binds the ChallengeContract deployed at address for the account of opts.
+func NewChainContract(address common.Address, backend ChainBackend, opts *bind.TransactOpts) *ChainContract {
+	return &ChainContract{
+		backend:  backend,
+		contract: contracts.NewChallengeContract(address, backend),
+		opts:     opts,
+	}
+}
+
+func (c *ChainContract) Account() common.Address {
+	return c.opts.From
+}
+
+func (c *ChainContract) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.backend.BlockNumber(ctx)
+}
+
+func (c *ChainContract) Challenge(ctx context.Context, id uint64) (contracts.Challenge, error) {
+	return c.contract.Challenges(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(id))
+}
+
+func (c *ChainContract) ChallengeDetails(ctx context.Context, id uint64) (contracts.ChallengeDetails, error) {
+	return c.contract.ChallengeDetails(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(id))
+}
+
+func (c *ChainContract) CreateChallenge(ctx context.Context, start uint64, end uint64, storageAddr common.Address, r *big.Int, point *big.Int, nameSpaceKey [32]byte) (uint64, error) {
+	receipt, err := c.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.CreateChallenge(opts, new(big.Int).SetUint64(start), new(big.Int).SetUint64(end), storageAddr, r, point, nameSpaceKey)
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, log := range receipt.Logs {
+		if ev, err := c.contract.ParseChallengeCreated(*log); err == nil {
+			return ev.Nonce.Uint64(), nil
+		}
+	}
+	return 0, fmt.Errorf("no ChallengeCreated event in transaction %s", receipt.TxHash)
+}
+
+func (c *ChainContract) SubmitAggregateCommitment(ctx context.Context, id uint64, commitment contracts.G1Point) error {
+	_, err := c.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.SubmitAggregateCommitment(opts, new(big.Int).SetUint64(id), commitment)
+	})
+	return err
+}
+
+func (c *ChainContract) SubmitOpinion(ctx context.Context, id uint64, agreed bool) error {
+	_, err := c.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.SubmitOpinion(opts, new(big.Int).SetUint64(id), agreed)
+	})
+	return err
+}
+
+func (c *ChainContract) UploadProof(ctx context.Context, id uint64, proof contracts.G1Point, value *big.Int) error {
+	_, err := c.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.UploadProof(opts, new(big.Int).SetUint64(id), proof, value)
+	})
+	return err
+}
+
+func (c *ChainContract) WatchChallengeCreated(ctx context.Context, sink chan<- *contracts.ChallengeCreated) (event.Subscription, error) {
+	return c.contract.WatchChallengeCreated(&bind.WatchOpts{Context: ctx}, sink)
+}
+
+// send sends the transaction built by transact and waits for its receipt.
+func (c *ChainContract) send(ctx context.Context, transact func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	opts := *c.opts
+	opts.Context = ctx
+	tx, err := transact(&opts)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := bind.WaitMined(ctx, c.backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %s", ErrReverted, tx.Hash())
+	}
+	return receipt, nil
+}
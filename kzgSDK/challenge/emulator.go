@@ -0,0 +1,465 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Emulator is an in-memory ChallengeContract. It mirrors createChallenge, submitAggregateCommitment,
+// submitOpinion and uploadProof, including their require checks, the 600-block timeouts and the
+// behaviour of the precompiles on invalid points, so that the parties of a challenge can be tested,
+// and disputes replayed, without a chain.
+//
+// Each successful transaction is mined in its own block, as on a simulated chain committing after
+// every transaction: it executes at BlockNumber()+1, which becomes the new block number. A reverted
+// transaction returns an error wrapping ErrReverted with the revert reason and changes nothing.
+type Emulator struct {
+	vk          kzg.VerifyingKey
+	commitments Source
+
+	mu         sync.Mutex
+	block      uint64
+	nonce      uint64
+	challenges map[uint64]*emulatedChallenge
+
+	createdFeed event.Feed
+	submitFeed  event.Feed
+}
+
+// emulatedChallenge merges the Challenge and ChallengeDetatils structs of the contract.
+type emulatedChallenge struct {
+	status              contracts.ChallengeStatus
+	challenger          common.Address
+	storageAddr         common.Address
+	nameSpaceKey        [32]byte
+	start               uint64
+	end                 uint64
+	r                   *big.Int
+	aggregateCommitment contracts.G1Point
+	point               *big.Int
+	timeoutBlock        uint64
+
+	consensusIndex          uint64
+	noConsensusIndex        uint64
+	currentIndex            uint64
+	consensusCommitment     contracts.G1Point
+	noConsensusCommitment   contracts.G1Point
+	currAggregateCommitment contracts.G1Point
+}
+
+// EmulatorConfig configures an Emulator.
+type EmulatorConfig struct {
+	// VerifyingKey is the key of the emulated Verifier, kzgsdk.DeployedVerifyingKey if nil. The
+	// deployed Verifier only accepts the proofs of the setup its constants come from, a test opening
+	// proofs with another srs sets the key of that srs.
+	VerifyingKey *kzg.VerifyingKey
+}
+
+// NewEmulator returns an Emulator reading the challenged commitments from commitments, the
+// stand-in of the CommitmentManager. As with getNameSpaceCommitment, a commitment the Source does
+// not hold is the zero point.
+func NewEmulator(commitments Source, config EmulatorConfig) *Emulator {
+	vk := kzgsdk.DeployedVerifyingKey()
+	if config.VerifyingKey != nil {
+		vk = *config.VerifyingKey
+	}
+	return &Emulator{
+		vk:          vk,
+		commitments: commitments,
+		challenges:  make(map[uint64]*emulatedChallenge),
+	}
+}
+
+// Account returns the Contract sending its transactions to the Emulator from addr.
+func (e *Emulator) Account(addr common.Address) Contract {
+	return &emulatorAccount{emulator: e, from: addr}
+}
+
+// BlockNumber returns the number of the last block.
+func (e *Emulator) BlockNumber() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.block
+}
+
+// Mine advances the chain by the given number of empty blocks.
+func (e *Emulator) Mine(blocks uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.block += blocks
+}
+
+// Nonce returns the id of the next challenge.
+func (e *Emulator) Nonce() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.nonce
+}
+
+// WatchAggregateCommitmentSubmit sends the AggregateCommitmentSubmit events to sink.
+func (e *Emulator) WatchAggregateCommitmentSubmit(sink chan<- *contracts.AggregateCommitmentSubmit) event.Subscription {
+	return e.submitFeed.Subscribe(sink)
+}
+
+func (e *Emulator) challenge(id uint64) contracts.Challenge {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch, ok := e.challenges[id]
+	if !ok {
+		ch = &emulatedChallenge{}
+	}
+	return contracts.Challenge{
+		Nonce:               new(big.Int).SetUint64(id),
+		Status:              ch.status,
+		Challenger:          ch.challenger,
+		StorageAddr:         ch.storageAddr,
+		NameSpaceKey:        ch.nameSpaceKey,
+		Start:               new(big.Int).SetUint64(ch.start),
+		End:                 new(big.Int).SetUint64(ch.end),
+		R:                   copyBigInt(ch.r),
+		AggregateCommitment: copyPoint(ch.aggregateCommitment),
+		Point:               copyBigInt(ch.point),
+		TimeoutBlock:        new(big.Int).SetUint64(ch.timeoutBlock),
+	}
+}
+
+func (e *Emulator) challengeDetails(id uint64) contracts.ChallengeDetails {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch, ok := e.challenges[id]
+	if !ok {
+		ch = &emulatedChallenge{}
+	}
+	return contracts.ChallengeDetails{
+		ConsensusIndex:          new(big.Int).SetUint64(ch.consensusIndex),
+		NoConsensusIndex:        new(big.Int).SetUint64(ch.noConsensusIndex),
+		CurrentIndex:            new(big.Int).SetUint64(ch.currentIndex),
+		ConsensusCommitment:     copyPoint(ch.consensusCommitment),
+		NoConsensusCommitment:   copyPoint(ch.noConsensusCommitment),
+		CurrAggregateCommitment: copyPoint(ch.currAggregateCommitment),
+	}
+}
+
+func (e *Emulator) createChallenge(from common.Address, start uint64, end uint64, storageAddr common.Address, r *big.Int, point *big.Int, nameSpaceKey [32]byte) (uint64, error) {
+	if !isUint256(r) || !isUint256(point) {
+		return 0, fmt.Errorf("%w: r or point is not a uint256", ErrReverted)
+	}
+	e.mu.Lock()
+	e.block++
+	id := e.nonce
+	ch := &emulatedChallenge{
+		status:       contracts.StatusChallengeCreated,
+		challenger:   from,
+		storageAddr:  storageAddr,
+		nameSpaceKey: nameSpaceKey,
+		start:        start,
+		end:          end,
+		r:            copyBigInt(r),
+		point:        copyBigInt(point),
+		timeoutBlock: e.block + TimeoutBlocks,
+	}
+	e.challenges[id] = ch
+	e.nonce++
+	ev := &contracts.ChallengeCreated{
+		Nonce:        new(big.Int).SetUint64(id),
+		StorageAddr:  storageAddr,
+		NameSpaceKey: nameSpaceKey,
+		Start:        new(big.Int).SetUint64(start),
+		End:          new(big.Int).SetUint64(end),
+		R:            copyBigInt(r),
+		TimeoutBlock: new(big.Int).SetUint64(ch.timeoutBlock),
+	}
+	e.mu.Unlock()
+	e.createdFeed.Send(ev)
+	return id, nil
+}
+
+func (e *Emulator) submitAggregateCommitment(from common.Address, id uint64, commitment contracts.G1Point) error {
+	if !isUint256(commitment.X) || !isUint256(commitment.Y) {
+		return fmt.Errorf("%w: commitment is not a pair of uint256", ErrReverted)
+	}
+	e.mu.Lock()
+	ch := e.lookup(id)
+	block := e.block + 1
+	switch {
+	case ch.status != contracts.StatusChallengeCreated && ch.status != contracts.StatusCommitNotAgreed:
+		e.mu.Unlock()
+		return revert("ChallengeContract: challenge is already complete")
+	case ch.timeoutBlock < block:
+		e.mu.Unlock()
+		return revert("ChallengeContract: timed out")
+	case from != ch.storageAddr:
+		e.mu.Unlock()
+		return revert("ChallengeContract: only the storage node can upload commitment")
+	}
+	e.block = block
+
+	if ch.status == contracts.StatusChallengeCreated {
+		ch.aggregateCommitment = copyPoint(commitment)
+		ch.status = contracts.StatusFirstCommitSubmitted
+	} else {
+		ch.currAggregateCommitment = copyPoint(commitment)
+		ch.status = contracts.StatusRecommitSubmitted
+	}
+	ev := &contracts.AggregateCommitmentSubmit{
+		Nonce:               new(big.Int).SetUint64(id),
+		AggregateCommitment: copyPoint(commitment),
+		TimeoutBlock:        new(big.Int).SetUint64(ch.timeoutBlock),
+	}
+	ch.timeoutBlock = block + TimeoutBlocks
+	e.mu.Unlock()
+	e.submitFeed.Send(ev)
+	return nil
+}
+
+func (e *Emulator) submitOpinion(ctx context.Context, from common.Address, id uint64, agreed bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch := e.lookup(id)
+	block := e.block + 1
+	switch {
+	case ch.challenger != from:
+		return revert("ChallengeContract: only the challenger can submit an opinion")
+	case ch.status != contracts.StatusFirstCommitSubmitted && ch.status != contracts.StatusRecommitSubmitted:
+		return revert("ChallengeContract: unsubmitted aggregate commitment")
+	case ch.timeoutBlock < block:
+		return revert("ChallengeContract: timed out")
+	}
+
+	// the handlers work on a copy, which is kept only if the transaction does not revert
+	next := *ch
+	var err error
+	if ch.status == contracts.StatusFirstCommitSubmitted {
+		handleInitialStatus(&next, agreed)
+	} else {
+		err = e.handleOngoingStatus(ctx, &next, agreed)
+	}
+	if err != nil {
+		return err
+	}
+	next.timeoutBlock = block + TimeoutBlocks
+	*ch = next
+	e.block = block
+	return nil
+}
+
+// handleInitialStatus mirrors ChallengeContract._handleInitialStatus.
+func handleInitialStatus(ch *emulatedChallenge, agreed bool) {
+	if agreed {
+		ch.status = contracts.StatusAgreementReached
+		return
+	}
+	ch.consensusIndex = ch.start
+	ch.noConsensusIndex = ch.end
+	ch.noConsensusCommitment = ch.aggregateCommitment
+	ch.currentIndex = midpoint(ch.start, ch.end)
+	ch.status = contracts.StatusCommitNotAgreed
+}
+
+// handleOngoingStatus mirrors ChallengeContract._handleOngoingStatus.
+func (e *Emulator) handleOngoingStatus(ctx context.Context, ch *emulatedChallenge, agreed bool) error {
+	if agreed {
+		ch.consensusIndex = ch.currentIndex
+		ch.consensusCommitment = ch.currAggregateCommitment
+		ch.status = contracts.StatusTemporaryAgreement
+	} else {
+		ch.noConsensusIndex = ch.currentIndex
+		ch.noConsensusCommitment = ch.currAggregateCommitment
+		ch.status = contracts.StatusCommitNotAgreed
+	}
+
+	if ch.noConsensusIndex == 0 {
+		return revert("arithmetic underflow")
+	}
+	if ch.consensusIndex == ch.noConsensusIndex-1 {
+		if ch.consensusIndex == ch.start {
+			commit, err := e.commitment(ctx, ch.nameSpaceKey, ch.start)
+			if err != nil {
+				return err
+			}
+			first := mulScalar(commit, hashFold(ch.r, ch.start))
+			ch.consensusCommitment = contracts.G1PointFromDigest(&first)
+		}
+		valid, err := e.verifyAggregateCommitment(ctx, ch)
+		if err != nil {
+			return err
+		}
+		if valid {
+			ch.status = contracts.StatusChallengeSuccessful
+		} else {
+			ch.status = contracts.StatusChallengeFailed
+		}
+	}
+
+	ch.currentIndex = midpoint(ch.noConsensusIndex, ch.consensusIndex)
+	return nil
+}
+
+// verifyAggregateCommitment mirrors the first ChallengeContract.verifyAggregateCommitment:
+// noConsensusCommitment == consensusCommitment + hashFold(r, noConsensusIndex)·commitment(noConsensusIndex).
+func (e *Emulator) verifyAggregateCommitment(ctx context.Context, ch *emulatedChallenge) (bool, error) {
+	commit, err := e.commitment(ctx, ch.nameSpaceKey, ch.noConsensusIndex)
+	if err != nil {
+		return false, err
+	}
+	consensus, err := ch.consensusCommitment.ToDigest()
+	if err != nil {
+		// Pairing.plus calls the ecAdd precompile, which fails on points outside of the curve
+		return false, revert("pairing-add-failed")
+	}
+	step := mulScalar(commit, hashFold(ch.r, ch.noConsensusIndex))
+	step.Add(&step, &consensus)
+	expected := contracts.G1PointFromDigest(&step)
+	// Pairing.equal compares the coordinates
+	return expected.X.Cmp(ch.noConsensusCommitment.X) == 0 && expected.Y.Cmp(ch.noConsensusCommitment.Y) == 0, nil
+}
+
+func (e *Emulator) uploadProof(from common.Address, id uint64, proof contracts.G1Point, value *big.Int) error {
+	if !isUint256(proof.X) || !isUint256(proof.Y) || !isUint256(value) {
+		return fmt.Errorf("%w: proof or value is not a uint256", ErrReverted)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch := e.lookup(id)
+	block := e.block + 1
+	switch {
+	case ch.status != contracts.StatusAgreementReached:
+		return revert("ChallengeContract: consensus on commitment not reached")
+	case ch.timeoutBlock < block:
+		return revert("ChallengeContract: timed out")
+	case from != ch.storageAddr:
+		return revert("ChallengeContract: only the storage node can upload commitment")
+	}
+	valid, err := verify(e.vk, ch.aggregateCommitment, proof, ch.point, value)
+	if err != nil {
+		return err
+	}
+	if valid {
+		ch.status = contracts.StatusChallengeSuccessful
+	} else {
+		ch.status = contracts.StatusChallengeFailed
+	}
+	e.block = block
+	return nil
+}
+
+// verify calls kzgsdk.VerifyLikeContractWithKey, its reverts reverting the transaction.
+func verify(vk kzg.VerifyingKey, commitment contracts.G1Point, proof contracts.G1Point, index *big.Int, value *big.Int) (bool, error) {
+	valid, err := kzgsdk.VerifyLikeContractWithKey([2]*big.Int{commitment.X, commitment.Y}, [2]*big.Int{proof.X, proof.Y}, index, value, vk)
+	if errors.Is(err, kzgsdk.ErrVerifierRevert) {
+		return false, fmt.Errorf("%w: %v", ErrReverted, err)
+	}
+	return valid, err
+}
+
+// commitment mirrors ChallengeContract.commitment.
+func (e *Emulator) commitment(ctx context.Context, nameSpaceKey [32]byte, index uint64) (kzg.Digest, error) {
+	commits, err := e.commitments.Commitments(ctx, nameSpaceKey, index, index)
+	if errors.Is(err, ErrUnknownData) {
+		return kzg.Digest{}, nil
+	}
+	if err != nil {
+		return kzg.Digest{}, err
+	}
+	return commits[0], nil
+}
+
+// lookup returns the challenge of the given id, a zero challenge if there is none, as the mapping
+// of the contract would. Must be called with e.mu held.
+func (e *Emulator) lookup(id uint64) *emulatedChallenge {
+	if ch, ok := e.challenges[id]; ok {
+		return ch
+	}
+	return &emulatedChallenge{}
+}
+
+// hashFold mirrors Hashing.hashFold on the raw r of a challenge.
+func hashFold(r *big.Int, n uint64) *big.Int {
+	hash := crypto.Keccak256(math.U256Bytes(copyBigInt(r)), math.U256Bytes(new(big.Int).SetUint64(n)))
+	return new(big.Int).SetBytes(hash)
+}
+
+// mulScalar mirrors Pairing.mulScalar: the ecMul precompile accepts any uint256 scalar.
+func mulScalar(p kzg.Digest, s *big.Int) kzg.Digest {
+	var result kzg.Digest
+	result.ScalarMultiplication(&p, new(big.Int).Mod(s, fr.Modulus()))
+	return result
+}
+
+// midpoint returns (a + b) / 2 without overflow.
+func midpoint(a uint64, b uint64) uint64 {
+	return a/2 + b/2 + a&b&1
+}
+
+func revert(reason string) error {
+	return fmt.Errorf("%w: %s", ErrReverted, reason)
+}
+
+func isUint256(v *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.BitLen() <= 256
+}
+
+func copyBigInt(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v)
+}
+
+func copyPoint(p contracts.G1Point) contracts.G1Point {
+	return contracts.G1Point{X: copyBigInt(p.X), Y: copyBigInt(p.Y)}
+}
+
+// emulatorAccount is the Contract of an account of an Emulator.
+type emulatorAccount struct {
+	emulator *Emulator
+	from     common.Address
+}
+
+func (a *emulatorAccount) Account() common.Address {
+	return a.from
+}
+
+func (a *emulatorAccount) BlockNumber(context.Context) (uint64, error) {
+	return a.emulator.BlockNumber(), nil
+}
+
+func (a *emulatorAccount) Challenge(_ context.Context, id uint64) (contracts.Challenge, error) {
+	return a.emulator.challenge(id), nil
+}
+
+func (a *emulatorAccount) ChallengeDetails(_ context.Context, id uint64) (contracts.ChallengeDetails, error) {
+	return a.emulator.challengeDetails(id), nil
+}
+
+func (a *emulatorAccount) CreateChallenge(_ context.Context, start uint64, end uint64, storageAddr common.Address, r *big.Int, point *big.Int, nameSpaceKey [32]byte) (uint64, error) {
+	return a.emulator.createChallenge(a.from, start, end, storageAddr, r, point, nameSpaceKey)
+}
+
+func (a *emulatorAccount) SubmitAggregateCommitment(_ context.Context, id uint64, commitment contracts.G1Point) error {
+	return a.emulator.submitAggregateCommitment(a.from, id, commitment)
+}
+
+func (a *emulatorAccount) SubmitOpinion(ctx context.Context, id uint64, agreed bool) error {
+	return a.emulator.submitOpinion(ctx, a.from, id, agreed)
+}
+
+func (a *emulatorAccount) UploadProof(_ context.Context, id uint64, proof contracts.G1Point, value *big.Int) error {
+	return a.emulator.uploadProof(a.from, id, proof, value)
+}
+
+func (a *emulatorAccount) WatchChallengeCreated(_ context.Context, sink chan<- *contracts.ChallengeCreated) (event.Subscription, error) {
+	return a.emulator.createdFeed.Subscribe(sink), nil
+}
@@ -0,0 +1,294 @@
+package challenge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// game is a namespace recorded in the CommitmentManager stand-in of an Emulator, and the blobs a
+// storage node keeps for it.
+type game struct {
+	sdk      *kzgsdk.DomiconSdk
+	emulator *Emulator
+	chain    *MemorySource
+	stored   *MemorySource
+}
+
+// newGame records n blobs, the storage node losing the blob at each index of lost and keeping
+// another blob, with its commitment, in its place. The Emulator verifies the proofs with the key
+// of the srs the blobs are committed with.
+func newGame(t *testing.T, n int, lost ...int) *game {
+	sdk := newTestSdk(t)
+	g := &game{sdk: sdk, chain: NewMemorySource(), stored: NewMemorySource()}
+	g.emulator = NewEmulator(g.chain, EmulatorConfig{VerifyingKey: &sdk.SRS().Vk})
+	for i := 0; i < n; i++ {
+		polynomial := randomPolynomial(2 + i%5)
+		commit, err := sdk.Commit(polynomial)
+		require.NoError(t, err)
+		g.chain.Append(nameSpaceKey, commit, polynomial)
+		for _, j := range lost {
+			if i == j {
+				polynomial = randomPolynomial(3)
+				commit, err = sdk.Commit(polynomial)
+				require.NoError(t, err)
+			}
+		}
+		g.stored.Append(nameSpaceKey, commit, polynomial)
+	}
+	return g
+}
+
+func (g *game) parties(t *testing.T) (*Responder, *Challenger) {
+	commits, err := g.chain.Commitments(context.Background(), nameSpaceKey, 0, uint64(len(g.chain.namespaces[nameSpaceKey].commits)-1))
+	require.NoError(t, err)
+	responder := NewResponder(g.sdk, g.emulator.Account(storageAddress), g.stored, ResponderConfig{})
+	challenger := NewChallenger(g.emulator.Account(challengerAddress), nameSpaceKey, commits, ChallengerConfig{})
+	return responder, challenger
+}
+
+// play steps both parties until they both stopped following the challenge, mining the blocks
+// of a timeout whenever neither of them can move.
+func (g *game) play(t *testing.T, responder *Responder, challenger *Challenger, id uint64) (Outcome, Outcome) {
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		block := g.emulator.BlockNumber()
+		require.NoError(t, responder.Step(ctx))
+		require.NoError(t, challenger.Step(ctx))
+		storageOutcome, storageDone := responder.Outcome(id)
+		challengerOutcome, challengerDone := challenger.Outcome(id)
+		if storageDone && challengerDone {
+			return storageOutcome, challengerOutcome
+		}
+		if g.emulator.BlockNumber() == block {
+			g.emulator.Mine(TimeoutBlocks)
+		}
+	}
+	t.Fatal("challenge did not end")
+	return Outcome{}, Outcome{}
+}
+
+func TestEmulatorHonestGame(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 16)
+	responder, challenger := g.parties(t)
+
+	id, err := challenger.Challenge(ctx, storageAddress, 3, 12)
+	require.NoError(t, err)
+	require.NoError(t, responder.Track(ctx, id))
+	storageOutcome, challengerOutcome := g.play(t, responder, challenger, id)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, storageOutcome)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, challengerOutcome)
+	// submission, opinion and proof
+	assert.Equal(t, uint64(4), g.emulator.BlockNumber())
+}
+
+func TestEmulatorDeployedVerifier(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 16)
+	// the SRS_G2[1] of the deployed Verifier is not the one of the SDK setup, an honest storage node
+	// agrees on every aggregate and still loses on the proof
+	g.emulator = NewEmulator(g.chain, EmulatorConfig{})
+	responder, challenger := g.parties(t)
+
+	id, err := challenger.Challenge(ctx, storageAddress, 3, 12)
+	require.NoError(t, err)
+	require.NoError(t, responder.Track(ctx, id))
+	storageOutcome, challengerOutcome := g.play(t, responder, challenger, id)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeFailed}, storageOutcome)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeFailed}, challengerOutcome)
+	assert.Equal(t, uint64(4), g.emulator.BlockNumber())
+}
+
+func TestEmulatorLargeR(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 16)
+	responder, challenger := g.parties(t)
+
+	// the contract folds with the unreduced r, both parties must too
+	for _, r := range []*big.Int{fr.Modulus(), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))} {
+		id, err := g.emulator.Account(challengerAddress).CreateChallenge(ctx, 3, 12, storageAddress, r, big.NewInt(7), nameSpaceKey)
+		require.NoError(t, err)
+		require.NoError(t, challenger.Track(ctx, id))
+		require.NoError(t, responder.Track(ctx, id))
+		storageOutcome, challengerOutcome := g.play(t, responder, challenger, id)
+		assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, storageOutcome, "r %x", r)
+		assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, challengerOutcome, "r %x", r)
+	}
+}
+
+func TestEmulatorLostBlob(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		lost     int
+		expected Outcome
+	}{
+		// the bisection only narrows down to the lost blob when the challenger never agrees
+		// before the last step: any earlier agreement leaves the challenge in TEMPORARY_AGREEMENT,
+		// where submitAggregateCommitment is not allowed, until it times out
+		{3, Outcome{Status: contracts.StatusChallengeFailed}},
+		{4, Outcome{Status: contracts.StatusChallengeFailed}},
+		{12, Outcome{Status: contracts.StatusTemporaryAgreement, Err: ErrTimedOut}},
+	} {
+		g := newGame(t, 16, tt.lost)
+		responder, challenger := g.parties(t)
+		id, err := challenger.Challenge(ctx, storageAddress, 3, 12)
+		require.NoError(t, err)
+		require.NoError(t, responder.Track(ctx, id))
+		storageOutcome, challengerOutcome := g.play(t, responder, challenger, id)
+		assert.Equal(t, tt.expected, storageOutcome, "lost %d", tt.lost)
+		assert.Equal(t, tt.expected, challengerOutcome, "lost %d", tt.lost)
+	}
+}
+
+func TestEmulatorBisection(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 8)
+	storage := g.emulator.Account(storageAddress)
+	challenger := g.emulator.Account(challengerAddress)
+	commits, err := g.chain.Commitments(ctx, nameSpaceKey, 0, 7)
+	require.NoError(t, err)
+
+	r, point := big.NewInt(11), big.NewInt(12)
+	gamma, _, err := kzgsdk.ChallengeElements(r, point)
+	require.NoError(t, err)
+	aggregate := func(start, end uint64) contracts.G1Point {
+		d, err := kzgsdk.AggregateRange(commits, gamma, start, end)
+		require.NoError(t, err)
+		return contracts.G1PointFromDigest(&d)
+	}
+
+	id, err := challenger.CreateChallenge(ctx, 1, 6, storageAddress, r, point, nameSpaceKey)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), g.emulator.Nonce())
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, aggregate(2, 6)))
+	require.NoError(t, challenger.SubmitOpinion(ctx, id, false))
+
+	details, err := challenger.ChallengeDetails(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), details.ConsensusIndex.Uint64())
+	assert.Equal(t, uint64(6), details.NoConsensusIndex.Uint64())
+	assert.Equal(t, uint64(3), details.CurrentIndex.Uint64())
+	assert.Equal(t, aggregate(2, 6), details.NoConsensusCommitment)
+
+	// disagreeing down to [start, start+1] checks the claim against aggregateCommitment(start)
+	for _, current := range []uint64{3, 2} {
+		require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, aggregate(2, current)))
+		require.NoError(t, challenger.SubmitOpinion(ctx, id, false))
+		details, err = challenger.ChallengeDetails(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, current, details.NoConsensusIndex.Uint64())
+	}
+	ch, err := challenger.Challenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.StatusChallengeFailed, ch.Status)
+	assert.Equal(t, aggregate(1, 1), details.ConsensusCommitment)
+
+	// a challenger disagreeing with an honest aggregate loses on the last step
+	id, err = challenger.CreateChallenge(ctx, 1, 6, storageAddress, r, point, nameSpaceKey)
+	require.NoError(t, err)
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, aggregate(2, 6)))
+	require.NoError(t, challenger.SubmitOpinion(ctx, id, false))
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, aggregate(1, 3)))
+	require.NoError(t, challenger.SubmitOpinion(ctx, id, false))
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, aggregate(1, 2)))
+	require.NoError(t, challenger.SubmitOpinion(ctx, id, true))
+	ch, err = challenger.Challenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.StatusChallengeSuccessful, ch.Status)
+}
+
+func TestEmulatorReverts(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 4)
+	storage := g.emulator.Account(storageAddress)
+	challenger := g.emulator.Account(challengerAddress)
+	commits, err := g.chain.Commitments(ctx, nameSpaceKey, 0, 3)
+	require.NoError(t, err)
+	point := contracts.G1PointFromDigest(&commits[0])
+
+	id, err := challenger.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	block := g.emulator.BlockNumber()
+
+	assert.ErrorIs(t, challenger.SubmitOpinion(ctx, id, true), ErrReverted)
+	assert.ErrorIs(t, storage.SubmitOpinion(ctx, id, true), ErrReverted)
+	assert.ErrorIs(t, challenger.SubmitAggregateCommitment(ctx, id, point), ErrReverted)
+	assert.ErrorIs(t, storage.UploadProof(ctx, id, point, big.NewInt(1)), ErrReverted)
+	assert.ErrorIs(t, storage.SubmitAggregateCommitment(ctx, id+1, point), ErrReverted)
+	assert.Equal(t, block, g.emulator.BlockNumber())
+
+	// the storage node may submit until timeoutBlock included
+	g.emulator.Mine(TimeoutBlocks - 1)
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, point))
+	assert.ErrorIs(t, storage.SubmitAggregateCommitment(ctx, id, point), ErrReverted)
+	g.emulator.Mine(TimeoutBlocks)
+	assert.ErrorIs(t, challenger.SubmitOpinion(ctx, id, true), ErrReverted)
+	ch, err := challenger.Challenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.StatusFirstCommitSubmitted, ch.Status)
+
+	// _handleOngoingStatus underflows on noConsensusIndex 0
+	id, err = challenger.CreateChallenge(ctx, 0, 0, storageAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, point))
+	require.NoError(t, challenger.SubmitOpinion(ctx, id, false))
+	require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, point))
+	assert.ErrorIs(t, challenger.SubmitOpinion(ctx, id, false), ErrReverted)
+	ch, err = challenger.Challenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.StatusRecommitSubmitted, ch.Status)
+}
+
+func TestEmulatorUploadProof(t *testing.T) {
+	ctx := context.Background()
+	g := newGame(t, 4)
+	storage := g.emulator.Account(storageAddress)
+	challenger := g.emulator.Account(challengerAddress)
+	commits, err := g.chain.Commitments(ctx, nameSpaceKey, 0, 3)
+	require.NoError(t, err)
+	polynomials, err := g.chain.Polynomials(ctx, nameSpaceKey, 0, 3)
+	require.NoError(t, err)
+
+	r, point := big.NewInt(21), big.NewInt(22)
+	gamma, openPoint, err := kzgsdk.ChallengeElements(r, point)
+	require.NoError(t, err)
+	aggregate, err := kzgsdk.AggregateRange(commits, gamma, 0, 3)
+	require.NoError(t, err)
+	proof, err := g.sdk.OpenRange(polynomials, openPoint, gamma, 0, 3)
+	require.NoError(t, err)
+	h := contracts.G1PointFromDigest(&proof.H)
+	value := proof.ClaimedValue.BigInt(new(big.Int))
+
+	for _, tt := range []struct {
+		proof    contracts.G1Point
+		value    *big.Int
+		reverted bool
+		status   contracts.ChallengeStatus
+	}{
+		{h, new(big.Int).Add(value, big.NewInt(1)), false, contracts.StatusChallengeFailed},
+		{h, fr.Modulus(), true, contracts.StatusAgreementReached},
+		{contracts.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}, value, true, contracts.StatusAgreementReached},
+		{h, value, false, contracts.StatusChallengeSuccessful},
+	} {
+		id, err := challenger.CreateChallenge(ctx, 0, 3, storageAddress, r, point, nameSpaceKey)
+		require.NoError(t, err)
+		require.NoError(t, storage.SubmitAggregateCommitment(ctx, id, contracts.G1PointFromDigest(&aggregate)))
+		require.NoError(t, challenger.SubmitOpinion(ctx, id, true))
+		err = storage.UploadProof(ctx, id, tt.proof, tt.value)
+		if tt.reverted {
+			assert.ErrorIs(t, err, ErrReverted)
+		} else {
+			assert.NoError(t, err)
+		}
+		ch, err := challenger.Challenge(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, ch.Status)
+	}
+}
@@ -0,0 +1,327 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrNotParticipant is returned when tracking a challenge the account does not take part in.
+var ErrNotParticipant = errors.New("account is not a party of the challenge")
+
+// DefaultPollInterval is the interval at which the challenges are polled when none is configured.
+const DefaultPollInterval = 2 * time.Second
+
+// Outcome is the end of a challenge as seen by one of its parties: a final status, or the error
+// that stopped the party from following the challenge, ErrTimedOut if the other party stalled.
+type Outcome struct {
+	Status contracts.ChallengeStatus
+	Err    error
+}
+
+// ResponderConfig configures a Responder.
+type ResponderConfig struct {
+	// PollInterval is the interval at which Run polls the tracked challenges, DefaultPollInterval if zero.
+	PollInterval time.Duration
+	// PrefixInterval is the checkpoint interval of the prefix aggregates, √n if zero.
+	PrefixInterval uint64
+	// CacheDir is the directory the prefix aggregates of the tracked challenges are saved to, so
+	// that Track resumes a challenge without computing them again. Nothing is saved if empty.
+	CacheDir string
+	// Parallel configures the folding of the blobs of the proofs read at once from a Source.
+	Parallel kzgsdk.ParallelConfig
+}
+
+// Responder is the storage-node side of the ChallengeContract. It answers the challenges of its
+// account: it submits CM_{start,end} to a new challenge, CM_{start,currentIndex} each time the
+// challenger disagrees, and the opening proof at point once both parties agree on the aggregate.
+//
+// The ChallengeContract only accepts a new aggregate after a disagreement: a challenge left in
+// TEMPORARY_AGREEMENT by the challenger cannot be answered and ends with ErrTimedOut.
+type Responder struct {
+	sdk      *kzgsdk.DomiconSdk
+	contract Contract
+	source   Source
+	config   ResponderConfig
+
+	mu       sync.Mutex
+	tracked  map[uint64]*response
+	outcomes map[uint64]Outcome
+}
+
+// response is the state kept for a tracked challenge.
+type response struct {
+	challenge contracts.Challenge
+	seed      kzgsdk.FoldSeed
+	openPoint fr.Element
+	prefix    *kzgsdk.PrefixAggregates
+}
+
+// NewResponder returns a Responder answering the challenges of the account of contract with the
+// blobs of source.
+func NewResponder(sdk *kzgsdk.DomiconSdk, contract Contract, source Source, config ResponderConfig) *Responder {
+	if config.PollInterval == 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	return &Responder{
+		sdk:      sdk,
+		contract: contract,
+		source:   source,
+		config:   config,
+		tracked:  make(map[uint64]*response),
+		outcomes: make(map[uint64]Outcome),
+	}
+}
+
+// Track starts answering the challenge of the given id, which must target the account of the
+// Responder. It is used by Run for the new challenges, and to resume challenges after a restart.
+func (r *Responder) Track(ctx context.Context, id uint64) error {
+	r.mu.Lock()
+	_, tracked := r.tracked[id]
+	_, finished := r.outcomes[id]
+	r.mu.Unlock()
+	if tracked || finished {
+		return nil
+	}
+
+	challenge, err := r.contract.Challenge(ctx, id)
+	if err != nil {
+		return err
+	}
+	if challenge.StorageAddr != r.contract.Account() {
+		return fmt.Errorf("%w: challenge %d targets %s", ErrNotParticipant, id, challenge.StorageAddr)
+	}
+	seed, openPoint, err := kzgsdk.ChallengeElements(challenge.R, challenge.Point)
+	if err != nil {
+		// Verifier.verify rejects a point outside of the scalar field, no proof can be accepted
+		r.finish(id, Outcome{Status: challenge.Status, Err: err})
+		return nil
+	}
+	start, end := challenge.Start.Uint64(), challenge.End.Uint64()
+	commits, err := r.source.Commitments(ctx, challenge.NameSpaceKey, start, end)
+	if err != nil {
+		return err
+	}
+	if err := checkRangeLen(len(commits), challenge.NameSpaceKey, start, end); err != nil {
+		return err
+	}
+	prefix, err := r.prefixAggregates(id, commits, seed, start)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tracked[id] = &response{
+		challenge: challenge,
+		seed:      seed,
+		openPoint: openPoint,
+		prefix:    prefix,
+	}
+	return nil
+}
+
+// prefixAggregates loads the prefix aggregates of a challenge from the cache directory, or computes
+// them and saves them there.
+func (r *Responder) prefixAggregates(id uint64, commits []kzg.Digest, seed kzgsdk.FoldSeed, start uint64) (*kzgsdk.PrefixAggregates, error) {
+	path := r.cachePath(id)
+	if path != "" {
+		prefix, err := kzgsdk.LoadPrefixAggregatesFrom(path, commits, start)
+		if err == nil && prefix.Seed() == seed {
+			return prefix, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warn("Discarding prefix aggregates", "id", id, "err", err)
+		}
+	}
+	prefix, err := kzgsdk.NewPrefixAggregatesFrom(commits, seed, start, r.config.PrefixInterval)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := kzgsdk.SavePrefixAggregates(path, prefix); err != nil {
+			log.Warn("Failed to save prefix aggregates", "id", id, "err", err)
+		}
+	}
+	return prefix, nil
+}
+
+// cachePath returns the file the prefix aggregates of a challenge are saved to, "" without CacheDir.
+func (r *Responder) cachePath(id uint64) string {
+	if r.config.CacheDir == "" {
+		return ""
+	}
+	return filepath.Join(r.config.CacheDir, fmt.Sprintf("challenge-%d", id))
+}
+
+// Outcome returns the outcome of a challenge the Responder stopped following.
+func (r *Responder) Outcome(id uint64) (Outcome, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	outcome, ok := r.outcomes[id]
+	return outcome, ok
+}
+
+// Pending returns the number of challenges being answered.
+func (r *Responder) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.tracked)
+}
+
+// Step acts once on every tracked challenge: it submits what the current status of the challenge
+// requests, and records the outcome of the challenges that ended. Errors do not stop the other
+// challenges, the failed ones are retried on the next Step.
+func (r *Responder) Step(ctx context.Context) error {
+	r.mu.Lock()
+	ids := make([]uint64, 0, len(r.tracked))
+	for id := range r.tracked {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	for _, id := range ids {
+		r.mu.Lock()
+		resp := r.tracked[id]
+		r.mu.Unlock()
+		if err := r.respond(ctx, id, resp); err != nil {
+			errs = append(errs, fmt.Errorf("challenge %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run answers the challenges created for the account until ctx is done, polling the tracked
+// challenges every PollInterval. It only returns the errors of the ChallengeCreated subscription,
+// the errors of a Step are logged and retried.
+func (r *Responder) Run(ctx context.Context) error {
+	created := make(chan *contracts.ChallengeCreated)
+	sub, err := r.contract.WatchChallengeCreated(ctx, created)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-created:
+			if ev.StorageAddr != r.contract.Account() {
+				continue
+			}
+			id := ev.Nonce.Uint64()
+			if err := r.Track(ctx, id); err != nil {
+				log.Warn("Failed to track challenge", "id", id, "err", err)
+				continue
+			}
+			log.Info("New challenge", "id", id, "start", ev.Start, "end", ev.End, "timeout", ev.TimeoutBlock)
+		case <-ticker.C:
+		case err := <-sub.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := r.Step(ctx); err != nil {
+			log.Warn("Failed to answer challenges", "err", err)
+		}
+	}
+}
+
+// respond submits what the current status of the challenge requests from the storage node.
+func (r *Responder) respond(ctx context.Context, id uint64, resp *response) error {
+	challenge, err := r.contract.Challenge(ctx, id)
+	if err != nil {
+		return err
+	}
+	if challenge.Status.Done() {
+		r.finish(id, Outcome{Status: challenge.Status})
+		return nil
+	}
+	block, err := r.contract.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	// a transaction sent now is included in the next block at the earliest
+	if block >= challenge.TimeoutBlock.Uint64() {
+		r.finish(id, Outcome{Status: challenge.Status, Err: ErrTimedOut})
+		return nil
+	}
+
+	switch challenge.Status {
+	case contracts.StatusChallengeCreated:
+		return r.submitAggregate(ctx, id, resp, resp.prefix.End())
+	case contracts.StatusCommitNotAgreed:
+		details, err := r.contract.ChallengeDetails(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.submitAggregate(ctx, id, resp, details.CurrentIndex.Uint64())
+	case contracts.StatusAgreementReached:
+		return r.uploadProof(ctx, id, resp)
+	}
+	// waiting for the challenger
+	return nil
+}
+
+// submitAggregate submits CM_{start,index}.
+func (r *Responder) submitAggregate(ctx context.Context, id uint64, resp *response, index uint64) error {
+	aggregate, err := resp.prefix.Aggregate(index)
+	if err != nil {
+		return err
+	}
+	return r.contract.SubmitAggregateCommitment(ctx, id, contracts.G1PointFromDigest(&aggregate))
+}
+
+// uploadProof uploads the opening at point of the polynomial committed by CM_{start,end}.
+func (r *Responder) uploadProof(ctx context.Context, id uint64, resp *response) error {
+	proof, err := r.openRange(ctx, resp)
+	if err != nil {
+		return err
+	}
+	return r.contract.UploadProof(ctx, id, contracts.G1PointFromDigest(&proof.H), proof.ClaimedValue.BigInt(new(big.Int)))
+}
+
+// openRange computes the opening at point of the polynomial committed by CM_{start,end}, folding
+// the blobs as they are read if the source is an IteratingSource, and with the workers of
+// Parallel otherwise.
+func (r *Responder) openRange(ctx context.Context, resp *response) (kzg.OpeningProof, error) {
+	start, end := resp.prefix.Start(), resp.prefix.End()
+	if source, ok := r.source.(IteratingSource); ok {
+		it := source.IteratePolynomials(resp.challenge.NameSpaceKey, start, end)
+		return r.sdk.OpenIterator(ctx, it, resp.openPoint, resp.seed, start, end)
+	}
+	polynomials, err := r.source.Polynomials(ctx, resp.challenge.NameSpaceKey, start, end)
+	if err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	if err := checkRangeLen(len(polynomials), resp.challenge.NameSpaceKey, start, end); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return r.sdk.OpenRangeFromParallel(polynomials, resp.openPoint, resp.seed, start, r.config.Parallel)
+}
+
+// finish stops following a challenge, removing its saved prefix aggregates.
+func (r *Responder) finish(id uint64, outcome Outcome) {
+	if path := r.cachePath(id); path != "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warn("Failed to remove prefix aggregates", "id", id, "err", err)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tracked, id)
+	r.outcomes[id] = outcome
+}
@@ -0,0 +1,459 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	storageAddress    = common.HexToAddress("0x5700000000000000000000000000000000000001")
+	challengerAddress = common.HexToAddress("0xc400000000000000000000000000000000000002")
+	nameSpaceKey      = [32]byte{0x4e, 0x53}
+)
+
+var errFakeRevert = errors.New("fake revert")
+
+type upload struct {
+	proof contracts.G1Point
+	value *big.Int
+}
+
+// fakeContract is a scripted stand-in of the ChallengeContract: it applies the status transitions
+// of the party under test, the test plays the other party by setting the status and the details.
+type fakeContract struct {
+	mu         sync.Mutex
+	account    common.Address
+	block      uint64
+	challenges map[uint64]*contracts.Challenge
+	details    map[uint64]*contracts.ChallengeDetails
+	submitted  []contracts.G1Point
+	uploads    []upload
+	opinions   []bool
+	feed       event.Feed
+	subscribed chan struct{}
+}
+
+func newFakeContract(account common.Address) *fakeContract {
+	return &fakeContract{
+		account:    account,
+		block:      100,
+		challenges: make(map[uint64]*contracts.Challenge),
+		details:    make(map[uint64]*contracts.ChallengeDetails),
+		subscribed: make(chan struct{}, 1),
+	}
+}
+
+func (c *fakeContract) Account() common.Address {
+	return c.account
+}
+
+func (c *fakeContract) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.block, nil
+}
+
+func (c *fakeContract) Challenge(_ context.Context, id uint64) (contracts.Challenge, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ch, ok := c.challenges[id]; ok {
+		return *ch, nil
+	}
+	return contracts.Challenge{Start: new(big.Int), End: new(big.Int), TimeoutBlock: new(big.Int)}, nil
+}
+
+func (c *fakeContract) ChallengeDetails(_ context.Context, id uint64) (contracts.ChallengeDetails, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.details[id], nil
+}
+
+func (c *fakeContract) CreateChallenge(_ context.Context, start uint64, end uint64, storageAddr common.Address, r *big.Int, point *big.Int, nameSpaceKey [32]byte) (uint64, error) {
+	c.mu.Lock()
+	id := uint64(len(c.challenges))
+	ch := &contracts.Challenge{
+		Nonce:        new(big.Int).SetUint64(id),
+		Status:       contracts.StatusChallengeCreated,
+		Challenger:   c.account,
+		StorageAddr:  storageAddr,
+		NameSpaceKey: nameSpaceKey,
+		Start:        new(big.Int).SetUint64(start),
+		End:          new(big.Int).SetUint64(end),
+		R:            r,
+		Point:        point,
+		TimeoutBlock: new(big.Int).SetUint64(c.block + TimeoutBlocks),
+	}
+	c.challenges[id] = ch
+	c.details[id] = &contracts.ChallengeDetails{CurrentIndex: new(big.Int)}
+	c.mu.Unlock()
+	c.feed.Send(&contracts.ChallengeCreated{
+		Nonce:        ch.Nonce,
+		StorageAddr:  storageAddr,
+		NameSpaceKey: nameSpaceKey,
+		Start:        ch.Start,
+		End:          ch.End,
+		R:            r,
+		TimeoutBlock: ch.TimeoutBlock,
+	})
+	return id, nil
+}
+
+func (c *fakeContract) SubmitAggregateCommitment(_ context.Context, id uint64, commitment contracts.G1Point) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := c.challenges[id]
+	switch ch.Status {
+	case contracts.StatusChallengeCreated:
+		ch.AggregateCommitment = commitment
+		ch.Status = contracts.StatusFirstCommitSubmitted
+	case contracts.StatusCommitNotAgreed:
+		c.details[id].CurrAggregateCommitment = commitment
+		ch.Status = contracts.StatusRecommitSubmitted
+	default:
+		return errFakeRevert
+	}
+	c.submitted = append(c.submitted, commitment)
+	return nil
+}
+
+func (c *fakeContract) SubmitOpinion(_ context.Context, id uint64, agreed bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := c.challenges[id]
+	switch {
+	case ch.Status == contracts.StatusFirstCommitSubmitted && agreed:
+		ch.Status = contracts.StatusAgreementReached
+	case ch.Status == contracts.StatusRecommitSubmitted && agreed:
+		ch.Status = contracts.StatusTemporaryAgreement
+	case ch.Status == contracts.StatusFirstCommitSubmitted || ch.Status == contracts.StatusRecommitSubmitted:
+		ch.Status = contracts.StatusCommitNotAgreed
+	default:
+		return errFakeRevert
+	}
+	c.opinions = append(c.opinions, agreed)
+	return nil
+}
+
+func (c *fakeContract) UploadProof(_ context.Context, id uint64, proof contracts.G1Point, value *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := c.challenges[id]
+	if ch.Status != contracts.StatusAgreementReached {
+		return errFakeRevert
+	}
+	c.uploads = append(c.uploads, upload{proof, value})
+	ch.Status = contracts.StatusChallengeSuccessful
+	return nil
+}
+
+func (c *fakeContract) WatchChallengeCreated(_ context.Context, sink chan<- *contracts.ChallengeCreated) (event.Subscription, error) {
+	sub := c.feed.Subscribe(sink)
+	c.subscribed <- struct{}{}
+	return sub, nil
+}
+
+// challengerMoves sets the status and current index of a challenge as a challenger would.
+func (c *fakeContract) challengerMoves(id uint64, status contracts.ChallengeStatus, currentIndex uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.challenges[id].Status = status
+	c.details[id].CurrentIndex = new(big.Int).SetUint64(currentIndex)
+}
+
+func (c *fakeContract) submissions() ([]contracts.G1Point, []upload) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]contracts.G1Point(nil), c.submitted...), append([]upload(nil), c.uploads...)
+}
+
+func randomPolynomial(n int) []fr.Element {
+	polynomial := make([]fr.Element, n)
+	for i := range polynomial {
+		polynomial[i].SetRandom()
+	}
+	return polynomial
+}
+
+// newNameSpace stores n random blobs in a MemorySource and returns their commitments.
+func newNameSpace(t *testing.T, sdk *kzgsdk.DomiconSdk, n int) (*MemorySource, []kzg.Digest) {
+	source := NewMemorySource()
+	commits := make([]kzg.Digest, n)
+	for i := range commits {
+		polynomial := randomPolynomial(2 + i%7)
+		commit, err := sdk.Commit(polynomial)
+		require.NoError(t, err)
+		commits[i] = commit
+		source.Append(nameSpaceKey, commit, polynomial)
+	}
+	return source, commits
+}
+
+func newTestSdk(t *testing.T) *kzgsdk.DomiconSdk {
+	sdk, err := kzgsdk.NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	return sdk
+}
+
+func TestResponderBisection(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	source, commits := newNameSpace(t, sdk, 12)
+	contract := newFakeContract(storageAddress)
+	responder := NewResponder(sdk, contract, source, ResponderConfig{})
+
+	r, point := big.NewInt(123456789), big.NewInt(987654321)
+	gamma, openPoint, err := kzgsdk.ChallengeElements(r, point)
+	require.NoError(t, err)
+	id, err := contract.CreateChallenge(ctx, 2, 9, storageAddress, r, point, nameSpaceKey)
+	require.NoError(t, err)
+	require.NoError(t, responder.Track(ctx, id))
+
+	aggregate := func(end uint64) contracts.G1Point {
+		d, err := kzgsdk.AggregateRange(commits, gamma, 2, end)
+		require.NoError(t, err)
+		return contracts.G1PointFromDigest(&d)
+	}
+
+	// CHALLENGE_CREATED: CM_{start,end}
+	require.NoError(t, responder.Step(ctx))
+	submitted, _ := contract.submissions()
+	assert.Equal(t, []contracts.G1Point{aggregate(9)}, submitted)
+
+	// nothing to do until the challenger answers
+	require.NoError(t, responder.Step(ctx))
+	submitted, _ = contract.submissions()
+	assert.Len(t, submitted, 1)
+
+	// COMMIT_NOT_AGREED: CM_{start,currentIndex}
+	contract.challengerMoves(id, contracts.StatusCommitNotAgreed, 5)
+	require.NoError(t, responder.Step(ctx))
+	contract.challengerMoves(id, contracts.StatusCommitNotAgreed, 3)
+	require.NoError(t, responder.Step(ctx))
+	submitted, _ = contract.submissions()
+	assert.Equal(t, []contracts.G1Point{aggregate(9), aggregate(5), aggregate(3)}, submitted)
+
+	// AGREEMENT_REACHED: opening of CM_{start,end} at point
+	contract.challengerMoves(id, contracts.StatusAgreementReached, 3)
+	require.NoError(t, responder.Step(ctx))
+	_, uploads := contract.submissions()
+	require.Len(t, uploads, 1)
+	commitment, err := aggregate(9).ToDigest()
+	require.NoError(t, err)
+	h, err := uploads[0].proof.ToDigest()
+	require.NoError(t, err)
+	proof := kzg.OpeningProof{H: h}
+	proof.ClaimedValue.SetBigInt(uploads[0].value)
+	assert.NoError(t, sdk.Verify(&commitment, &proof, openPoint))
+
+	require.NoError(t, responder.Step(ctx))
+	outcome, ok := responder.Outcome(id)
+	assert.True(t, ok)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeSuccessful}, outcome)
+	assert.Zero(t, responder.Pending())
+}
+
+// iteratingSource serves the blobs of a MemorySource one at a time only.
+type iteratingSource struct {
+	*MemorySource
+}
+
+func (s iteratingSource) Polynomials(context.Context, [32]byte, uint64, uint64) ([][]fr.Element, error) {
+	return nil, errors.New("range read of an IteratingSource")
+}
+
+func (s iteratingSource) IteratePolynomials(nameSpaceKey [32]byte, start uint64, end uint64) kzgsdk.PolynomialIterator {
+	polynomials, err := s.MemorySource.Polynomials(context.Background(), nameSpaceKey, start, end)
+	return &memoryIterator{polynomials: polynomials, start: start, err: err}
+}
+
+type memoryIterator struct {
+	polynomials [][]fr.Element
+	start, next uint64
+	err         error
+}
+
+func (it *memoryIterator) Next(context.Context) bool {
+	if it.err != nil || it.next == uint64(len(it.polynomials)) {
+		return false
+	}
+	it.next++
+	return true
+}
+
+func (it *memoryIterator) Index() uint64 {
+	return it.start + it.next - 1
+}
+
+func (it *memoryIterator) Polynomial() []fr.Element {
+	return it.polynomials[it.next-1]
+}
+
+func (it *memoryIterator) Err() error {
+	return it.err
+}
+
+func TestResponderIteratingSource(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	source, _ := newNameSpace(t, sdk, 12)
+	r, point := big.NewInt(123456789), big.NewInt(987654321)
+
+	// the same proof is uploaded whether the blobs are read at once or one at a time
+	var uploaded []contracts.G1Point
+	for _, s := range []Source{source, iteratingSource{source}} {
+		contract := newFakeContract(storageAddress)
+		responder := NewResponder(sdk, contract, s, ResponderConfig{})
+		id, err := contract.CreateChallenge(ctx, 2, 9, storageAddress, r, point, nameSpaceKey)
+		require.NoError(t, err)
+		require.NoError(t, responder.Track(ctx, id))
+		require.NoError(t, responder.Step(ctx))
+		contract.challengerMoves(id, contracts.StatusAgreementReached, 9)
+		require.NoError(t, responder.Step(ctx))
+		_, uploads := contract.submissions()
+		require.Len(t, uploads, 1)
+		uploaded = append(uploaded, uploads[0].proof)
+	}
+	assert.Equal(t, uploaded[0], uploaded[1])
+}
+
+func TestResponderTimeout(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	source, _ := newNameSpace(t, sdk, 4)
+	contract := newFakeContract(storageAddress)
+	responder := NewResponder(sdk, contract, source, ResponderConfig{})
+
+	id, err := contract.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	require.NoError(t, responder.Track(ctx, id))
+	contract.block += TimeoutBlocks
+
+	require.NoError(t, responder.Step(ctx))
+	submitted, _ := contract.submissions()
+	assert.Empty(t, submitted)
+	outcome, ok := responder.Outcome(id)
+	assert.True(t, ok)
+	assert.Equal(t, Outcome{Status: contracts.StatusChallengeCreated, Err: ErrTimedOut}, outcome)
+}
+
+func TestResponderTrack(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	source, _ := newNameSpace(t, sdk, 4)
+	contract := newFakeContract(storageAddress)
+	responder := NewResponder(sdk, contract, source, ResponderConfig{})
+
+	id, err := contract.CreateChallenge(ctx, 0, 3, challengerAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	assert.ErrorIs(t, responder.Track(ctx, id), ErrNotParticipant)
+
+	id, err = contract.CreateChallenge(ctx, 0, 4, storageAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	assert.ErrorIs(t, responder.Track(ctx, id), ErrUnknownData)
+	assert.Zero(t, responder.Pending())
+
+	// no proof is accepted for a point outside of the scalar field
+	id, err = contract.CreateChallenge(ctx, 0, 3, storageAddress, big.NewInt(1), fr.Modulus(), nameSpaceKey)
+	require.NoError(t, err)
+	assert.NoError(t, responder.Track(ctx, id))
+	outcome, ok := responder.Outcome(id)
+	assert.True(t, ok)
+	assert.ErrorIs(t, outcome.Err, kzgsdk.ErrPointOutOfField)
+
+	// but the contract folds with any r
+	id, err = contract.CreateChallenge(ctx, 0, 3, storageAddress, fr.Modulus(), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	assert.NoError(t, responder.Track(ctx, id))
+	_, ok = responder.Outcome(id)
+	assert.False(t, ok)
+	assert.Equal(t, 1, responder.Pending())
+}
+
+func TestResponderCacheDir(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	source, commits := newNameSpace(t, sdk, 12)
+	contract := newFakeContract(storageAddress)
+	dir := t.TempDir()
+	r, point := big.NewInt(123456789), big.NewInt(987654321)
+	seed, _, err := kzgsdk.ChallengeElements(r, point)
+	require.NoError(t, err)
+	id, err := contract.CreateChallenge(ctx, 2, 9, storageAddress, r, point, nameSpaceKey)
+	require.NoError(t, err)
+
+	// a cache left by another challenge under the same id is replaced
+	path := filepath.Join(dir, fmt.Sprintf("challenge-%d", id))
+	staleSeed, err := kzgsdk.ChallengeSeed(big.NewInt(1))
+	require.NoError(t, err)
+	stale, err := kzgsdk.NewPrefixAggregatesFrom(commits[2:10], staleSeed, 2, 0)
+	require.NoError(t, err)
+	require.NoError(t, kzgsdk.SavePrefixAggregates(path, stale))
+
+	responder := NewResponder(sdk, contract, source, ResponderConfig{CacheDir: dir})
+	require.NoError(t, responder.Track(ctx, id))
+	saved, err := kzgsdk.LoadPrefixAggregatesFrom(path, commits[2:10], 2)
+	require.NoError(t, err)
+	assert.Equal(t, seed, saved.Seed())
+
+	// a restarted responder resumes from the saved aggregates
+	restarted := NewResponder(sdk, contract, source, ResponderConfig{CacheDir: dir})
+	require.NoError(t, restarted.Track(ctx, id))
+	require.NoError(t, restarted.Step(ctx))
+	expected, err := kzgsdk.AggregateRange(commits, seed, 2, 9)
+	require.NoError(t, err)
+	submitted, _ := contract.submissions()
+	assert.Equal(t, []contracts.G1Point{contracts.G1PointFromDigest(&expected)}, submitted)
+
+	// and removes them once the challenge ended
+	contract.block += TimeoutBlocks
+	require.NoError(t, restarted.Step(ctx))
+	_, ok := restarted.Outcome(id)
+	assert.True(t, ok)
+	assert.NoFileExists(t, path)
+}
+
+func TestResponderRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sdk := newTestSdk(t)
+	source, _ := newNameSpace(t, sdk, 4)
+	contract := newFakeContract(storageAddress)
+	responder := NewResponder(sdk, contract, source, ResponderConfig{PollInterval: 10 * time.Millisecond})
+
+	done := make(chan error)
+	go func() { done <- responder.Run(ctx) }()
+	<-contract.subscribed
+
+	_, err := contract.CreateChallenge(ctx, 0, 3, challengerAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	id, err := contract.CreateChallenge(ctx, 1, 3, storageAddress, big.NewInt(1), big.NewInt(2), nameSpaceKey)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		submitted, _ := contract.submissions()
+		return len(submitted) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	contract.challengerMoves(id, contracts.StatusAgreementReached, 0)
+	assert.Eventually(t, func() bool {
+		_, ok := responder.Outcome(id)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,373 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrStalled is reported for a simulated game that does not converge, which happens when the
+// challenger disagrees on a challenge with start == end: currentIndex never moves.
+var ErrStalled = errors.New("challenge does not converge")
+
+// maxRounds bounds the number of opinions of a simulated game, a bisection needs about log2(end-start).
+const maxRounds = 128
+
+var (
+	simulatedStorage    = common.HexToAddress("0x0000000000000000000000000000000000005707")
+	simulatedChallenger = common.HexToAddress("0x000000000000000000000000000000000000c4a1")
+)
+
+// StorageStep is what a simulated storage node knows when submitting the aggregate CM_{Start,Index}.
+type StorageStep struct {
+	// Round is the number of opinions submitted so far.
+	Round int
+	Start uint64
+	Index uint64
+	Seed  kzgsdk.FoldSeed
+	// Honest is the aggregate of the commitments recorded on chain.
+	Honest kzg.Digest
+	// Last reports whether a disagreement with this aggregate ends the bisection.
+	Last bool
+}
+
+// StorageStrategy is the behaviour of a simulated storage node.
+type StorageStrategy interface {
+	// Aggregate returns the aggregate submitted at a step.
+	Aggregate(step StorageStep) kzg.Digest
+	// Proof returns the proof uploaded once the aggregate is agreed on, honest being the opening
+	// of the aggregate of the blobs recorded on chain.
+	Proof(honest kzg.OpeningProof) kzg.OpeningProof
+	String() string
+}
+
+// ChallengerStep is what a simulated challenger knows when judging the aggregate CM_{start,Index}.
+type ChallengerStep struct {
+	// Round is the number of opinions submitted so far.
+	Round int
+	Index uint64
+	// Agreed reports whether the aggregate is the one computed by the challenger.
+	Agreed bool
+	// Last reports whether the honest opinion ends the bisection.
+	Last bool
+}
+
+// ChallengerStrategy is the behaviour of a simulated challenger.
+type ChallengerStrategy interface {
+	// Opinion returns the opinion submitted at a step.
+	Opinion(step ChallengerStep) bool
+	String() string
+}
+
+// HonestStorage submits the aggregates and the proof of the blobs recorded on chain.
+type HonestStorage struct{}
+
+func (HonestStorage) Aggregate(step StorageStep) kzg.Digest          { return step.Honest }
+func (HonestStorage) Proof(honest kzg.OpeningProof) kzg.OpeningProof { return honest }
+func (HonestStorage) String() string                                 { return "honest" }
+
+// WrongAggregateAt is a storage node holding a wrong blob at Index: every aggregate over it is wrong.
+type WrongAggregateAt struct {
+	Index uint64
+}
+
+func (s WrongAggregateAt) Aggregate(step StorageStep) kzg.Digest {
+	if step.Index < s.Index || s.Index < step.Start {
+		return step.Honest
+	}
+	// the commitment at Index is off by the generator
+	coefficient := step.Seed.Coefficient(s.Index)
+	return offset(step.Honest, coefficient.BigInt(new(big.Int)))
+}
+func (WrongAggregateAt) Proof(honest kzg.OpeningProof) kzg.OpeningProof { return honest }
+func (s WrongAggregateAt) String() string                               { return fmt.Sprintf("wrong aggregate at %d", s.Index) }
+
+// LieFirstStorage submits a wrong first aggregate, and honest aggregates afterwards.
+type LieFirstStorage struct{}
+
+func (LieFirstStorage) Aggregate(step StorageStep) kzg.Digest {
+	if step.Round == 0 {
+		return offset(step.Honest, big.NewInt(1))
+	}
+	return step.Honest
+}
+func (LieFirstStorage) Proof(honest kzg.OpeningProof) kzg.OpeningProof { return honest }
+func (LieFirstStorage) String() string                                 { return "lie at the first step" }
+
+// LieAtEndStorage submits honest aggregates, except the one a disagreement would check.
+type LieAtEndStorage struct{}
+
+func (LieAtEndStorage) Aggregate(step StorageStep) kzg.Digest {
+	if step.Last {
+		return offset(step.Honest, big.NewInt(1))
+	}
+	return step.Honest
+}
+func (LieAtEndStorage) Proof(honest kzg.OpeningProof) kzg.OpeningProof { return honest }
+func (LieAtEndStorage) String() string                                 { return "lie at the end" }
+
+// ForgedProofStorage submits honest aggregates, and a proof of a wrong value.
+type ForgedProofStorage struct{}
+
+func (ForgedProofStorage) Aggregate(step StorageStep) kzg.Digest { return step.Honest }
+func (ForgedProofStorage) Proof(honest kzg.OpeningProof) kzg.OpeningProof {
+	var one fr.Element
+	one.SetOne()
+	honest.ClaimedValue.Add(&honest.ClaimedValue, &one)
+	return honest
+}
+func (ForgedProofStorage) String() string { return "forged proof" }
+
+// HonestChallenger agrees with the aggregates matching its own.
+type HonestChallenger struct{}
+
+func (HonestChallenger) Opinion(step ChallengerStep) bool { return step.Agreed }
+func (HonestChallenger) String() string                   { return "honest" }
+
+// LieFirstChallenger submits the wrong opinion on the first aggregate, honest ones afterwards.
+type LieFirstChallenger struct{}
+
+func (LieFirstChallenger) Opinion(step ChallengerStep) bool { return step.Agreed != (step.Round == 0) }
+func (LieFirstChallenger) String() string                   { return "lie at the first step" }
+
+// LieAtEndChallenger submits honest opinions, except the one that would end the bisection.
+type LieAtEndChallenger struct{}
+
+func (LieAtEndChallenger) Opinion(step ChallengerStep) bool { return step.Agreed != step.Last }
+func (LieAtEndChallenger) String() string                   { return "lie at the end" }
+
+// AlwaysDisagreeChallenger disagrees with every aggregate.
+type AlwaysDisagreeChallenger struct{}
+
+func (AlwaysDisagreeChallenger) Opinion(ChallengerStep) bool { return false }
+func (AlwaysDisagreeChallenger) String() string              { return "always disagree" }
+
+// offset returns d + s·G, G being the generator of G1.
+func offset(d kzg.Digest, s *big.Int) kzg.Digest {
+	_, _, g1, _ := bn254.Generators()
+	var result kzg.Digest
+	result.ScalarMultiplication(&g1, s)
+	result.Add(&result, &d)
+	return result
+}
+
+// MoveKind is the kind of a move of a challenge game.
+type MoveKind uint8
+
+const (
+	MoveCreate MoveKind = iota
+	MoveSubmit
+	MoveOpinion
+	MoveProof
+	MoveTimeout
+)
+
+// Move is a transaction of a challenge game, or its timeout.
+type Move struct {
+	Kind MoveKind
+	// Block is the block of the transaction, the timeout block for MoveTimeout.
+	Block uint64
+	// Index is the end of the submitted or judged aggregate.
+	Index uint64
+	// Agreed is the opinion of a MoveOpinion.
+	Agreed bool
+	// Honest reports whether the move is the one an honest party would have made.
+	Honest bool
+	// Status is the status of the challenge after the move.
+	Status contracts.ChallengeStatus
+}
+
+func (m Move) String() string {
+	var move string
+	switch m.Kind {
+	case MoveCreate:
+		move = "challenger creates the challenge"
+	case MoveSubmit:
+		move = fmt.Sprintf("storage submits CM_{start,%d}", m.Index)
+	case MoveOpinion:
+		move = fmt.Sprintf("challenger submits opinion %t on CM_{start,%d}", m.Agreed, m.Index)
+	case MoveProof:
+		move = "storage uploads the proof"
+	case MoveTimeout:
+		return fmt.Sprintf("block %d: timed out in %s", m.Block, m.Status)
+	}
+	if !m.Honest {
+		move += " (dishonest)"
+	}
+	return fmt.Sprintf("block %d: %s -> %s", m.Block, move, m.Status)
+}
+
+// Report is the result of a simulated challenge game.
+type Report struct {
+	Storage    string
+	Challenger string
+	Start      uint64
+	End        uint64
+	// Rounds is the number of opinions submitted by the challenger.
+	Rounds     int
+	Outcome    Outcome
+	Transcript []Move
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "storage %s, challenger %s, [%d, %d]: %s after %d rounds", r.Storage, r.Challenger, r.Start, r.End, r.Outcome.Status, r.Rounds)
+	if r.Outcome.Err != nil {
+		fmt.Fprintf(&b, " (%v)", r.Outcome.Err)
+	}
+	for _, move := range r.Transcript {
+		fmt.Fprintf(&b, "\n\t%s", move)
+	}
+	return b.String()
+}
+
+// Simulator plays challenge games over a namespace between simulated storage nodes and challengers,
+// on an Emulator of the ChallengeContract. The Emulator verifies the proofs with the key of the srs
+// of the Simulator, as a Verifier whose constants were written for that srs would.
+type Simulator struct {
+	sdk         *kzgsdk.DomiconSdk
+	chain       *MemorySource
+	commits     []kzg.Digest
+	polynomials [][]fr.Element
+	rand        io.Reader
+}
+
+// NewSimulator returns a Simulator of the namespace holding polynomials, drawing the r and point
+// of the challenges from rand, crypto/rand if nil. A deterministic rand replays the same games.
+func NewSimulator(sdk *kzgsdk.DomiconSdk, polynomials [][]fr.Element, rand io.Reader) (*Simulator, error) {
+	s := &Simulator{
+		sdk:         sdk,
+		chain:       NewMemorySource(),
+		commits:     make([]kzg.Digest, len(polynomials)),
+		polynomials: polynomials,
+		rand:        rand,
+	}
+	for i, polynomial := range polynomials {
+		commit, err := sdk.Commit(polynomial)
+		if err != nil {
+			return nil, err
+		}
+		s.commits[i] = commit
+		s.chain.Append([32]byte{}, commit, polynomial)
+	}
+	return s, nil
+}
+
+// Play plays a challenge over [start, end] until it ends or times out.
+func (s *Simulator) Play(ctx context.Context, start uint64, end uint64, storage StorageStrategy, challenger ChallengerStrategy) (*Report, error) {
+	r, point, err := RandomChallengeElements(s.rand)
+	if err != nil {
+		return nil, err
+	}
+	seed, openPoint, err := kzgsdk.ChallengeElements(r, point)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := kzgsdk.NewPrefixAggregates(s.commits, seed, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	emulator := NewEmulator(s.chain, EmulatorConfig{VerifyingKey: &s.sdk.SRS().Vk})
+	storageAccount := emulator.Account(simulatedStorage)
+	challengerAccount := emulator.Account(simulatedChallenger)
+	report := &Report{
+		Storage:    storage.String(),
+		Challenger: challenger.String(),
+		Start:      start,
+		End:        end,
+	}
+	id, err := challengerAccount.CreateChallenge(ctx, start, end, simulatedStorage, r, point, [32]byte{})
+	if err != nil {
+		return nil, err
+	}
+	move := Move{Kind: MoveCreate, Honest: true}
+
+	for {
+		ch := emulator.challenge(id)
+		details := emulator.challengeDetails(id)
+		move.Block, move.Status = emulator.BlockNumber(), ch.Status
+		report.Transcript = append(report.Transcript, move)
+		if ch.Status.Done() {
+			report.Outcome = Outcome{Status: ch.Status}
+			return report, nil
+		}
+		if report.Rounds > maxRounds {
+			report.Outcome = Outcome{Status: ch.Status, Err: ErrStalled}
+			return report, nil
+		}
+		if ch.Status == contracts.StatusTemporaryAgreement {
+			// no transaction is accepted in this status
+			emulator.Mine(ch.TimeoutBlock.Uint64() + 1 - emulator.BlockNumber())
+			report.Transcript = append(report.Transcript, Move{Kind: MoveTimeout, Block: ch.TimeoutBlock.Uint64(), Status: ch.Status})
+			report.Outcome = Outcome{Status: ch.Status, Err: ErrTimedOut}
+			return report, nil
+		}
+
+		switch ch.Status {
+		case contracts.StatusChallengeCreated, contracts.StatusCommitNotAgreed:
+			step := StorageStep{Round: report.Rounds, Start: start, Index: end, Seed: seed}
+			if ch.Status == contracts.StatusCommitNotAgreed {
+				step.Index = details.CurrentIndex.Uint64()
+				step.Last = details.ConsensusIndex.Uint64()+1 == step.Index
+			}
+			if step.Honest, err = prefix.Aggregate(step.Index); err != nil {
+				return nil, err
+			}
+			aggregate := storage.Aggregate(step)
+			move = Move{Kind: MoveSubmit, Index: step.Index, Honest: aggregate.Equal(&step.Honest)}
+			if err := storageAccount.SubmitAggregateCommitment(ctx, id, contracts.G1PointFromDigest(&aggregate)); err != nil {
+				return nil, fmt.Errorf("%s: %w", move, err)
+			}
+
+		case contracts.StatusFirstCommitSubmitted, contracts.StatusRecommitSubmitted:
+			step := ChallengerStep{Round: report.Rounds, Index: end}
+			submitted := ch.AggregateCommitment
+			if ch.Status == contracts.StatusRecommitSubmitted {
+				step.Index, submitted = details.CurrentIndex.Uint64(), details.CurrAggregateCommitment
+			}
+			expected, err := prefix.Aggregate(step.Index)
+			if err != nil {
+				return nil, err
+			}
+			aggregate, invalid := submitted.ToDigest()
+			step.Agreed = invalid == nil && aggregate.Equal(&expected)
+			step.Last = step.Agreed
+			if ch.Status == contracts.StatusRecommitSubmitted {
+				consensus, noConsensus := details.ConsensusIndex.Uint64(), details.NoConsensusIndex.Uint64()
+				if step.Agreed {
+					step.Last = step.Index+1 == noConsensus
+				} else {
+					step.Last = consensus+1 == step.Index
+				}
+			}
+			opinion := challenger.Opinion(step)
+			report.Rounds++
+			move = Move{Kind: MoveOpinion, Index: step.Index, Agreed: opinion, Honest: opinion == step.Agreed}
+			if err := challengerAccount.SubmitOpinion(ctx, id, opinion); err != nil {
+				return nil, fmt.Errorf("%s: %w", move, err)
+			}
+
+		case contracts.StatusAgreementReached:
+			honest, err := s.sdk.OpenRange(s.polynomials, openPoint, seed, start, end)
+			if err != nil {
+				return nil, err
+			}
+			proof := storage.Proof(honest)
+			move = Move{Kind: MoveProof, Honest: proof.H.Equal(&honest.H) && proof.ClaimedValue.Equal(&honest.ClaimedValue)}
+			if err := storageAccount.UploadProof(ctx, id, contracts.G1PointFromDigest(&proof.H), proof.ClaimedValue.BigInt(new(big.Int))); err != nil {
+				return nil, fmt.Errorf("%s: %w", move, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package challenge
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSimulator(t *testing.T, seed int64) *Simulator {
+	r := rand.New(rand.NewSource(seed))
+	polynomials := make([][]fr.Element, 16)
+	for i := range polynomials {
+		polynomials[i] = make([]fr.Element, 2+r.Intn(6))
+		for j := range polynomials[i] {
+			polynomials[i][j].SetUint64(r.Uint64())
+		}
+	}
+	s, err := NewSimulator(newTestSdk(t), polynomials, r)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSimulator(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSimulator(t, 1)
+
+	var (
+		successful = Outcome{Status: contracts.StatusChallengeSuccessful}
+		failed     = Outcome{Status: contracts.StatusChallengeFailed}
+		stuck      = Outcome{Status: contracts.StatusTemporaryAgreement, Err: ErrTimedOut}
+	)
+	for _, tt := range []struct {
+		start, end uint64
+		storage    StorageStrategy
+		challenger ChallengerStrategy
+		outcome    Outcome
+		rounds     int
+	}{
+		{2, 13, HonestStorage{}, HonestChallenger{}, successful, 1},
+		{2, 13, ForgedProofStorage{}, HonestChallenger{}, failed, 1},
+		// the storage node is caught when the challenger disagrees down to [start, start+1]
+		{2, 13, WrongAggregateAt{2}, HonestChallenger{}, failed, 4},
+		{2, 13, WrongAggregateAt{3}, HonestChallenger{}, failed, 4},
+		{2, 3, WrongAggregateAt{3}, HonestChallenger{}, failed, 2},
+		{2, 13, LieAtEndStorage{}, AlwaysDisagreeChallenger{}, failed, 4},
+		// an agreement before the last step leaves the challenge in TEMPORARY_AGREEMENT, in which
+		// the contract accepts no aggregate: neither a lying storage node nor a lying challenger
+		// is caught, the challenge times out
+		{2, 13, WrongAggregateAt{8}, HonestChallenger{}, stuck, 2},
+		{2, 13, WrongAggregateAt{13}, HonestChallenger{}, stuck, 2},
+		{2, 13, LieFirstStorage{}, HonestChallenger{}, stuck, 2},
+		{2, 13, HonestStorage{}, LieFirstChallenger{}, stuck, 2},
+		{2, 13, HonestStorage{}, LieAtEndChallenger{}, stuck, 2},
+		// a lie that is never checked goes unnoticed
+		{2, 13, LieAtEndStorage{}, HonestChallenger{}, successful, 1},
+		// a challenger disagreeing with honest aggregates loses on the last step
+		{2, 13, HonestStorage{}, AlwaysDisagreeChallenger{}, successful, 4},
+		{5, 5, HonestStorage{}, AlwaysDisagreeChallenger{}, Outcome{Status: contracts.StatusCommitNotAgreed, Err: ErrStalled}, maxRounds + 1},
+	} {
+		report, err := s.Play(ctx, tt.start, tt.end, tt.storage, tt.challenger)
+		require.NoError(t, err)
+		t.Log(report)
+		assert.Equal(t, tt.outcome, report.Outcome, "storage %s, challenger %s", tt.storage, tt.challenger)
+		assert.Equal(t, tt.rounds, report.Rounds, "storage %s, challenger %s", tt.storage, tt.challenger)
+
+		last := report.Transcript[len(report.Transcript)-1]
+		assert.Equal(t, report.Outcome.Status, last.Status)
+		assert.Equal(t, MoveCreate, report.Transcript[0].Kind)
+		opinions := 0
+		for _, move := range report.Transcript {
+			if move.Kind == MoveOpinion {
+				opinions++
+			}
+		}
+		assert.Equal(t, report.Rounds, opinions)
+	}
+}
+
+func TestSimulatorTranscript(t *testing.T) {
+	ctx := context.Background()
+	report, err := newTestSimulator(t, 7).Play(ctx, 2, 13, WrongAggregateAt{3}, HonestChallenger{})
+	require.NoError(t, err)
+
+	kinds := make([]MoveKind, len(report.Transcript))
+	dishonest := 0
+	for i, move := range report.Transcript {
+		kinds[i] = move.Kind
+		if !move.Honest {
+			dishonest++
+			assert.Equal(t, MoveSubmit, move.Kind)
+		}
+	}
+	assert.Equal(t, []MoveKind{MoveCreate, MoveSubmit, MoveOpinion, MoveSubmit, MoveOpinion, MoveSubmit, MoveOpinion, MoveSubmit, MoveOpinion}, kinds)
+	assert.Equal(t, 4, dishonest)
+	// each transaction is mined in its own block
+	for i, move := range report.Transcript {
+		assert.Equal(t, uint64(i+1), move.Block)
+	}
+
+	// the same seed replays the same game
+	replay, err := newTestSimulator(t, 7).Play(ctx, 2, 13, WrongAggregateAt{3}, HonestChallenger{})
+	require.NoError(t, err)
+	assert.Equal(t, report.String(), replay.String())
+}
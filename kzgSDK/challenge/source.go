@@ -0,0 +1,98 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+)
+
+// ErrUnknownData is returned by a Source that does not hold the requested namespace indices.
+var ErrUnknownData = errors.New("namespace data not found")
+
+// Source gives a storage node access to the blobs it stores. Both methods return the values of
+// the inclusive range [start, end] of namespace indices, the first element being the one at start.
+type Source interface {
+	// Commitments returns the commitments recorded by the CommitmentManager for the range.
+	Commitments(ctx context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([]kzg.Digest, error)
+	// Polynomials returns the blobs of the range as polynomials.
+	Polynomials(ctx context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([][]fr.Element, error)
+}
+
+// IteratingSource is a Source that also reads the blobs of a range one at a time. The Responder
+// folds the blobs of such a Source as they are read, rather than holding the whole range in memory.
+type IteratingSource interface {
+	Source
+	// IteratePolynomials returns an iterator over the blobs of the range as polynomials.
+	IteratePolynomials(nameSpaceKey [32]byte, start uint64, end uint64) kzgsdk.PolynomialIterator
+}
+
+// MemorySource is a Source holding the blobs of each namespace in memory.
+type MemorySource struct {
+	mu         sync.RWMutex
+	namespaces map[[32]byte]*memoryNameSpace
+}
+
+type memoryNameSpace struct {
+	commits     []kzg.Digest
+	polynomials [][]fr.Element
+}
+
+// NewMemorySource returns an empty MemorySource.
+func NewMemorySource() *MemorySource {
+	return &MemorySource{namespaces: make(map[[32]byte]*memoryNameSpace)}
+}
+
+// Append adds a blob and its commitment at the next index of the namespace, and returns that index.
+func (s *MemorySource) Append(nameSpaceKey [32]byte, commit kzg.Digest, polynomial []fr.Element) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns, ok := s.namespaces[nameSpaceKey]
+	if !ok {
+		ns = &memoryNameSpace{}
+		s.namespaces[nameSpaceKey] = ns
+	}
+	ns.commits = append(ns.commits, commit)
+	ns.polynomials = append(ns.polynomials, polynomial)
+	return uint64(len(ns.commits) - 1)
+}
+
+func (s *MemorySource) Commitments(_ context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([]kzg.Digest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ns, err := s.namespace(nameSpaceKey, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return append([]kzg.Digest(nil), ns.commits[start:end+1]...), nil
+}
+
+func (s *MemorySource) Polynomials(_ context.Context, nameSpaceKey [32]byte, start uint64, end uint64) ([][]fr.Element, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ns, err := s.namespace(nameSpaceKey, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return append([][]fr.Element(nil), ns.polynomials[start:end+1]...), nil
+}
+
+func (s *MemorySource) namespace(nameSpaceKey [32]byte, start uint64, end uint64) (*memoryNameSpace, error) {
+	ns, ok := s.namespaces[nameSpaceKey]
+	if !ok || start > end || end >= uint64(len(ns.commits)) {
+		return nil, fmt.Errorf("%w: [%d, %d] of namespace %x", ErrUnknownData, start, end, nameSpaceKey)
+	}
+	return ns, nil
+}
+
+// checkRangeLen makes sure a Source returned the n values of the range [start, end].
+func checkRangeLen(n int, nameSpaceKey [32]byte, start uint64, end uint64) error {
+	if uint64(n) != end-start+1 {
+		return fmt.Errorf("%w: %d values for [%d, %d] of namespace %x", ErrUnknownData, n, start, end, nameSpaceKey)
+	}
+	return nil
+}
@@ -3,13 +3,14 @@ package main
 import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
 )
 
 const DemoPolynomialsNum = 3
 const DemoBenchSize = 129
 
 func main() {
-	srs, err := SRSFromSol()
+	sdk, err := kzgsdk.NewDomiconSdkFromSol()
 	if err != nil {
 		panic(err)
 	}
@@ -17,20 +18,23 @@ func main() {
 	cs := make([]kzg.Digest, DemoPolynomialsNum)
 	for i := 0; i < DemoPolynomialsNum; i++ {
 		ps[i] = DemoRandomPolynomial(DemoBenchSize / 2)
-		cs[i], _ = kzg.Commit(ps[i], srs.Pk)
+		cs[i], _ = sdk.Commit(ps[i])
 	}
 	var r fr.Element
 	r.SetRandom()
 	//crate random number open as open point
 	var open fr.Element
 	open.SetRandom()
-	proof := Responce(ps, open, r, srs)
+	proof, err := sdk.Open(ps, open, r)
+	if err != nil {
+		panic(err)
+	}
 	var FoldedCommit kzg.Digest
-	FoldedCommit, err = FoldedCommits(cs, r, 0, DemoPolynomialsNum)
+	FoldedCommit, err = sdk.Fold(cs, r, 0, DemoPolynomialsNum)
 	if err != nil {
 		panic(err)
 	}
-	err = kzg.Verify(&FoldedCommit, &proof, open, srs.Vk)
+	err = sdk.Verify(&FoldedCommit, &proof, open)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,72 @@
+// Command solconstants writes src/kzg/Constants.sol for an srs, or checks an existing one.
+//
+//	go run ./cmd/solconstants -out Constants.sol
+//	go run ./cmd/solconstants -deployed -check ../src/kzg/Constants.sol
+//	go run ./cmd/solconstants -ptau powersOfTau28_hez_final_20.ptau -size 1048576 -check ../src/kzg/Constants.sol
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+)
+
+func main() {
+	srsPath := flag.String("srs", "", "srs file written by GenerateSRSFile, defaults to SRSFromSol")
+	deployed := flag.Bool("deployed", false, "use the verifying key of the deployed Constants.sol as the srs")
+	ptauPath := flag.String("ptau", "", "snarkjs powers-of-tau file to read the srs from")
+	size := flag.Uint64("size", 128, "number of G1 powers to read from the ptau file")
+	numG1 := flag.Int("g1", 1, "number of G1 powers to write")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	check := flag.String("check", "", "Constants.sol file to check against the srs instead of writing one")
+	flag.Parse()
+
+	if err := run(*srsPath, *deployed, *ptauPath, *size, *numG1, *out, *check); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(srsPath string, deployed bool, ptauPath string, size uint64, numG1 int, out, check string) error {
+	var srs *kzg.SRS
+	var err error
+	switch {
+	case deployed:
+		vk := kzgsdk.DeployedVerifyingKey()
+		srs = &kzg.SRS{Pk: kzg.ProvingKey{G1: []bn254.G1Affine{vk.G1}}, Vk: vk}
+	case ptauPath != "":
+		srs, err = kzgsdk.ImportPtau(ptauPath, size)
+	case srsPath != "":
+		srs, err = kzgsdk.LoadSRS(srsPath)
+	default:
+		srs, err = kzgsdk.SRSFromSol()
+	}
+	if err != nil {
+		return err
+	}
+
+	if check != "" {
+		file, err := os.Open(check)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return kzgsdk.CheckSolidityConstants(file, srs)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return kzgsdk.WriteSolidityConstants(w, srs, numG1)
+}
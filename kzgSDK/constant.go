@@ -1,11 +1,11 @@
-package main
+package kzgsdk
 
 import (
 	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
 	"github.com/status-im/keycard-go/hexutils"
-	"math/big"
 	"os"
 )
 
@@ -285,23 +285,10 @@ func SRSFromSol() (*kzg.SRS, error) {
 	G2_Y_1 := [...]string{
 		"12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa",
 		"2d58022915fc6bc90e036e858fbc98055084ac7aff98ccceb0e3fde64bc1a084"}
-	srs, err := kzg.NewSRS(129, big.NewInt(42))
-	if err != nil {
-		return nil, err
-	}
-	for i, g1 := range srs.Pk.G1 {
-		g1.X.SetBytes(hexutils.HexToBytes(G1_X[i]))
-		g1.Y.SetBytes(hexutils.HexToBytes(G1_Y[i]))
-		if !g1.IsOnCurve() {
-			println("not OnCurve")
-		}
-	}
-	for i := 0; i < 129; i++ {
+	srs := &kzg.SRS{Pk: kzg.ProvingKey{G1: make([]bn254.G1Affine, len(G1_X))}}
+	for i := 0; i < len(G1_X); i++ {
 		srs.Pk.G1[i].X.SetBytes(hexutils.HexToBytes(G1_X[i]))
 		srs.Pk.G1[i].Y.SetBytes(hexutils.HexToBytes(G1_Y[i]))
-		if !srs.Pk.G1[i].IsOnCurve() {
-			println("G1 is not OnCurve")
-		}
 	}
 
 	srs.Vk.G1 = srs.Pk.G1[0]
@@ -317,11 +304,8 @@ func SRSFromSol() (*kzg.SRS, error) {
 	srs.Vk.G2[0].Y.A0.SetBytes(hexutils.HexToBytes(G2_Y_1[0]))
 	srs.Vk.G2[0].Y.A1.SetBytes(hexutils.HexToBytes(G2_Y_0[0]))
 
-	if !srs.Vk.G2[0].IsOnCurve() {
-		println("G2[0] is not OnCurve ")
-	}
-	if !srs.Vk.G2[1].IsOnCurve() {
-		println("G2[1] is not OnCurve ")
+	if err := ValidateSRS(srs); err != nil {
+		return nil, err
 	}
 	return srs, nil
 }
@@ -462,7 +446,7 @@ func GenerateSRSFile() error {
 		return err
 	}
 	defer file.Close()
-	quickSrs.WriteTo(file)
+	err = WriteSRS(file, quickSrs)
 	if err != nil {
 		fmt.Println("write file failed, ", err)
 		return err
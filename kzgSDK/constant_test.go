@@ -1,4 +1,4 @@
-package main
+package kzgsdk
 
 import (
 	"crypto/rand"
@@ -130,6 +130,13 @@ func TestGenerateSRSFile(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	srs, err := LoadSRS("./srs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solSrs, _ := SRSFromSol()
+	assert.Equal(t, solSrs.Pk.G1, srs.Pk.G1)
+	assert.Equal(t, solSrs.Vk, srs.Vk)
 
 }
 
@@ -146,7 +153,7 @@ func TestSRSPerformance(t *testing.T) {
 	}
 	time0 := time.Now()
 
-	gamma := dataToPolynomial(data)
+	gamma := DataToPolynomial(data)
 	time1 := time.Now()
 	// compute ∑ᵢγⁱfᵢ
 	commit, _ := kzg.Commit(gamma, srs.Pk)
@@ -204,7 +211,7 @@ func TestSRSPerformanceTwo(t *testing.T) {
 			fmt.Println("Error generating random data:", err)
 			return
 		}
-		polynomial := dataToPolynomial(data)
+		polynomial := DataToPolynomial(data)
 		commit, _ := kzg.Commit(polynomial, srs.Pk)
 		polynomials[i] = polynomial
 		commits[i] = commit
@@ -0,0 +1,194 @@
+// Package contracts provides Go bindings for the contracts of src/: ChallengeContract,
+// CommitmentManager, NodeManager and StorageManager.
+//
+// The ABIs below only hold the functions and events used off chain, they follow the
+// Solidity sources and must be updated along with them.
+package contracts
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// g1PointComponents is the ABI of a Pairing.G1Point tuple.
+const g1PointComponents = `"components":[{"internalType":"uint256","name":"X","type":"uint256"},{"internalType":"uint256","name":"Y","type":"uint256"}],"internalType":"struct Pairing.G1Point"`
+
+// nodeInfoComponents is the ABI of a NodeInfo tuple.
+const nodeInfoComponents = `"components":[` +
+	`{"internalType":"string","name":"url","type":"string"},` +
+	`{"internalType":"string","name":"name","type":"string"},` +
+	`{"internalType":"uint256","name":"stakedTokens","type":"uint256"},` +
+	`{"internalType":"string","name":"location","type":"string"},` +
+	`{"internalType":"uint256","name":"maxStorageSpace","type":"uint256"},` +
+	`{"internalType":"address","name":"addr","type":"address"}]`
+
+// ChallengeContractABIJSON is the ABI of src/ChallengeContract.sol.
+const ChallengeContractABIJSON = `[
+{"type":"function","name":"createChallenge","stateMutability":"payable","inputs":[
+	{"internalType":"uint256","name":"_start","type":"uint256"},
+	{"internalType":"uint256","name":"_end","type":"uint256"},
+	{"internalType":"address","name":"_storageAddr","type":"address"},
+	{"internalType":"uint256","name":"_r","type":"uint256"},
+	{"internalType":"uint256","name":"_point","type":"uint256"},
+	{"internalType":"bytes32","name":"_nameSpaceKey","type":"bytes32"}],"outputs":[]},
+{"type":"function","name":"submitAggregateCommitment","stateMutability":"nonpayable","inputs":[
+	{"internalType":"uint256","name":"_challengeId","type":"uint256"},
+	{` + g1PointComponents + `,"name":"_commitment","type":"tuple"}],"outputs":[]},
+{"type":"function","name":"submitOpinion","stateMutability":"nonpayable","inputs":[
+	{"internalType":"uint256","name":"_challengeId","type":"uint256"},
+	{"internalType":"bool","name":"_agreed","type":"bool"}],"outputs":[]},
+{"type":"function","name":"uploadProof","stateMutability":"nonpayable","inputs":[
+	{"internalType":"uint256","name":"_challengeId","type":"uint256"},
+	{` + g1PointComponents + `,"name":"_proof","type":"tuple"},
+	{"internalType":"uint256","name":"_value","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"challenges","stateMutability":"view","inputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}],"outputs":[
+	{"internalType":"uint256","name":"nonce","type":"uint256"},
+	{"internalType":"uint8","name":"status","type":"uint8"},
+	{"internalType":"address","name":"challenger","type":"address"},
+	{"internalType":"address","name":"storageAddr","type":"address"},
+	{"internalType":"bytes32","name":"nameSpaceKey","type":"bytes32"},
+	{"internalType":"uint256","name":"start","type":"uint256"},
+	{"internalType":"uint256","name":"end","type":"uint256"},
+	{"internalType":"uint256","name":"r","type":"uint256"},
+	{` + g1PointComponents + `,"name":"aggregateCommitment","type":"tuple"},
+	{"internalType":"uint256","name":"point","type":"uint256"},
+	{"internalType":"uint256","name":"timeoutBlock","type":"uint256"}]},
+{"type":"function","name":"challengeDetailsMap","stateMutability":"view","inputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}],"outputs":[
+	{"internalType":"uint256","name":"consensusIndex","type":"uint256"},
+	{"internalType":"uint256","name":"noConsensusIndex","type":"uint256"},
+	{"internalType":"uint256","name":"currentIndex","type":"uint256"},
+	{` + g1PointComponents + `,"name":"consensusCommitment","type":"tuple"},
+	{` + g1PointComponents + `,"name":"noConsensusCommitment","type":"tuple"},
+	{` + g1PointComponents + `,"name":"currAggregateCommitment","type":"tuple"}]},
+{"type":"function","name":"nonce","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}]},
+{"type":"function","name":"commitmentManager","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"contract CommitmentManager","name":"","type":"address"}]},
+{"type":"function","name":"nodeManager","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"contract NodeManager","name":"","type":"address"}]},
+{"type":"event","name":"ChallengeCreated","anonymous":false,"inputs":[
+	{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"},
+	{"indexed":false,"internalType":"address","name":"storageAddr","type":"address"},
+	{"indexed":false,"internalType":"bytes32","name":"nameSpaceKey","type":"bytes32"},
+	{"indexed":false,"internalType":"uint256","name":"start","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"end","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"r","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"timeoutBlock","type":"uint256"}]},
+{"type":"event","name":"AggregateCommitmentSubmit","anonymous":false,"inputs":[
+	{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"},
+	{` + g1PointComponents + `,"indexed":false,"name":"aggregateCommitment","type":"tuple"},
+	{"indexed":false,"internalType":"uint256","name":"timeoutBlock","type":"uint256"}]}
+]`
+
+// CommitmentManagerABIJSON is the ABI of src/CommitmentManager.sol.
+const CommitmentManagerABIJSON = `[
+{"type":"function","name":"submitCommitment","stateMutability":"payable","inputs":[
+	{"internalType":"uint256","name":"_length","type":"uint256"},
+	{"internalType":"uint256","name":"_timeout","type":"uint256"},
+	{"internalType":"bytes32","name":"_nameSpaceKey","type":"bytes32"},
+	{"internalType":"bytes32","name":"_nodeGroupKey","type":"bytes32"},
+	{"internalType":"bytes[]","name":"_signatures","type":"bytes[]"},
+	{` + g1PointComponents + `,"name":"_commitment","type":"tuple"}],"outputs":[]},
+{"type":"function","name":"baseFee","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}]},
+{"type":"function","name":"nonce","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}]},
+{"type":"function","name":"indices","stateMutability":"view","inputs":[
+	{"internalType":"address","name":"","type":"address"}],"outputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}]},
+{"type":"function","name":"nameSpaceIndex","stateMutability":"view","inputs":[
+	{"internalType":"bytes32","name":"","type":"bytes32"}],"outputs":[
+	{"internalType":"uint256","name":"","type":"uint256"}]},
+{"type":"function","name":"getNameSpaceCommitment","stateMutability":"view","inputs":[
+	{"internalType":"bytes32","name":"_nameSpaceKey","type":"bytes32"},
+	{"internalType":"uint256","name":"_index","type":"uint256"}],"outputs":[
+	{` + g1PointComponents + `,"name":"","type":"tuple"}]},
+{"type":"function","name":"getUserCommitment","stateMutability":"view","inputs":[
+	{"internalType":"address","name":"_user","type":"address"},
+	{"internalType":"uint256","name":"_index","type":"uint256"}],"outputs":[
+	{` + g1PointComponents + `,"name":"","type":"tuple"}]},
+{"type":"function","name":"nodeManager","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"contract NodeManager","name":"","type":"address"}]},
+{"type":"function","name":"storageManagement","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"contract StorageManager","name":"","type":"address"}]},
+{"type":"event","name":"SendDACommitment","anonymous":false,"inputs":[
+	{` + g1PointComponents + `,"indexed":false,"name":"commitment","type":"tuple"},
+	{"indexed":false,"internalType":"uint256","name":"timestamp","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"index","type":"uint256"},
+	{"indexed":false,"internalType":"uint256","name":"timeout","type":"uint256"},
+	{"indexed":false,"internalType":"bytes32","name":"nodeGroupKey","type":"bytes32"},
+	{"indexed":false,"internalType":"bytes32","name":"nameSpaceKey","type":"bytes32"},
+	{"indexed":false,"internalType":"bytes[]","name":"signatures","type":"bytes[]"}]}
+]`
+
+// NodeManagerABIJSON is the ABI of src/NodeManager.sol.
+const NodeManagerABIJSON = `[
+{"type":"function","name":"registerBroadcastNode","stateMutability":"nonpayable","inputs":[
+	{` + nodeInfoComponents + `,"internalType":"struct NodeInfo","name":"info","type":"tuple"}],"outputs":[]},
+{"type":"function","name":"registerStorageNode","stateMutability":"nonpayable","inputs":[
+	{` + nodeInfoComponents + `,"internalType":"struct NodeInfo","name":"info","type":"tuple"}],"outputs":[]},
+{"type":"function","name":"getBroadcastingNodes","stateMutability":"view","inputs":[],"outputs":[
+	{` + nodeInfoComponents + `,"internalType":"struct NodeInfo[]","name":"nodes","type":"tuple[]"}]},
+{"type":"function","name":"getStorageNodes","stateMutability":"view","inputs":[],"outputs":[
+	{` + nodeInfoComponents + `,"internalType":"struct NodeInfo[]","name":"nodes","type":"tuple[]"}]},
+{"type":"function","name":"isNodeBroadcast","stateMutability":"view","inputs":[
+	{"internalType":"address","name":"addr","type":"address"}],"outputs":[
+	{"internalType":"bool","name":"","type":"bool"}]},
+{"type":"function","name":"isNodeStorage","stateMutability":"view","inputs":[
+	{"internalType":"address","name":"addr","type":"address"}],"outputs":[
+	{"internalType":"bool","name":"","type":"bool"}]}
+]`
+
+// StorageManagerABIJSON is the ABI of src/StorageManager.sol.
+const StorageManagerABIJSON = `[
+{"type":"function","name":"registerNodeGroup","stateMutability":"nonpayable","inputs":[
+	{"internalType":"uint256","name":"_requiredAmountOfSignatures","type":"uint256"},
+	{"internalType":"address[]","name":"_nodeAddresses","type":"address[]"}],"outputs":[
+	{"internalType":"bytes32","name":"nodeGroupKey","type":"bytes32"}]},
+{"type":"function","name":"registerNameSpace","stateMutability":"nonpayable","inputs":[
+	{"internalType":"address[]","name":"_nodeAddresses","type":"address[]"}],"outputs":[
+	{"internalType":"bytes32","name":"nameSpaceKey","type":"bytes32"}]},
+{"type":"function","name":"NODEGROUP","stateMutability":"view","inputs":[
+	{"internalType":"bytes32","name":"_key","type":"bytes32"}],"outputs":[
+	{"components":[
+		{"internalType":"uint256","name":"requiredAmountOfSignatures","type":"uint256"},
+		{"internalType":"address[]","name":"addrs","type":"address[]"}],
+	"internalType":"struct NodeGroup","name":"","type":"tuple"}]},
+{"type":"function","name":"NAMESPACE","stateMutability":"view","inputs":[
+	{"internalType":"bytes32","name":"_key","type":"bytes32"}],"outputs":[
+	{"components":[
+		{"internalType":"address","name":"creator","type":"address"},
+		{"internalType":"address[]","name":"addr","type":"address[]"}],
+	"internalType":"struct NameSpace","name":"","type":"tuple"}]},
+{"type":"function","name":"nodeManager","stateMutability":"view","inputs":[],"outputs":[
+	{"internalType":"contract NodeManager","name":"","type":"address"}]},
+{"type":"event","name":"NodeGroupRegistered","anonymous":false,"inputs":[
+	{"indexed":true,"internalType":"address","name":"creator","type":"address"},
+	{"indexed":true,"internalType":"bytes32","name":"key","type":"bytes32"},
+	{"indexed":false,"internalType":"uint256","name":"requiredAmountOfSignatures","type":"uint256"},
+	{"indexed":false,"internalType":"address[]","name":"nodeAddresses","type":"address[]"}]},
+{"type":"event","name":"NameSpaceRegistered","anonymous":false,"inputs":[
+	{"indexed":true,"internalType":"address","name":"creator","type":"address"},
+	{"indexed":true,"internalType":"bytes32","name":"key","type":"bytes32"},
+	{"indexed":false,"internalType":"address[]","name":"nodeAddresses","type":"address[]"}]}
+]`
+
+// Parsed ABIs of the contracts, used to pack calldata and decode logs without a bound contract.
+var (
+	ChallengeContractABI = mustParseABI(ChallengeContractABIJSON)
+	CommitmentManagerABI = mustParseABI(CommitmentManagerABIJSON)
+	NodeManagerABI       = mustParseABI(NodeManagerABIJSON)
+	StorageManagerABI    = mustParseABI(StorageManagerABIJSON)
+)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,140 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// ErrUnexpectedLog is returned when decoding a log emitted by another contract.
+var ErrUnexpectedLog = errors.New("log was not emitted by the contract")
+
+// boundContract is the part shared by the bindings: the address and the bind.BoundContract of a contract.
+type boundContract struct {
+	address  common.Address
+	contract *bind.BoundContract
+}
+
+func newBoundContract(address common.Address, parsed abi.ABI, backend bind.ContractBackend) boundContract {
+	return boundContract{
+		address:  address,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}
+}
+
+// Address returns the address of the contract.
+func (c *boundContract) Address() common.Address {
+	return c.address
+}
+
+// call calls a view method and returns its outputs.
+func (c *boundContract) call(opts *bind.CallOpts, method string, params ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, method, params...)
+	return out, err
+}
+
+// callBigInt calls a view method returning a single uint256.
+func (c *boundContract) callBigInt(opts *bind.CallOpts, method string, params ...interface{}) (*big.Int, error) {
+	out, err := c.call(opts, method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// callAddress calls a view method returning a single address.
+func (c *boundContract) callAddress(opts *bind.CallOpts, method string, params ...interface{}) (common.Address, error) {
+	out, err := c.call(opts, method, params...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// unpackLog decodes a log of event into out, checking that the log was emitted by the contract.
+func unpackLog(c *boundContract, out interface{}, event string, log types.Log) error {
+	if log.Address != c.address {
+		return fmt.Errorf("%w: %s, expected %s", ErrUnexpectedLog, log.Address, c.address)
+	}
+	return c.contract.UnpackLog(out, event, log)
+}
+
+// filterEvents returns the decoded events of name in the range of opts.
+func filterEvents[T any](c *boundContract, opts *bind.FilterOpts, name string, parse func(types.Log) (*T, error), query ...[]interface{}) ([]*T, error) {
+	logs, sub, err := c.contract.FilterLogs(opts, name, query...)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+	var events []*T
+	add := func(log types.Log) error {
+		ev, err := parse(log)
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+		return nil
+	}
+	// logs is never closed, the subscription ends once every log has been delivered
+	for {
+		select {
+		case log := <-logs:
+			if err := add(log); err != nil {
+				return nil, err
+			}
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			for {
+				select {
+				case log := <-logs:
+					if err := add(log); err != nil {
+						return nil, err
+					}
+				default:
+					return events, nil
+				}
+			}
+		}
+	}
+}
+
+// watchEvents subscribes to the events of name and sends them, decoded, to sink.
+// The subscription fails with the decoding error of the first malformed log.
+func watchEvents[T any](c *boundContract, opts *bind.WatchOpts, name string, sink chan<- *T, parse func(types.Log) (*T, error), query ...[]interface{}) (event.Subscription, error) {
+	logs, sub, err := c.contract.WatchLogs(opts, name, query...)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				ev, err := parse(log)
+				if err != nil {
+					return err
+				}
+				select {
+				case sink <- ev:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,137 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackend answers contract calls with canned outputs, records the sent transactions
+// and serves a fixed set of logs.
+type fakeBackend struct {
+	outputs map[string][]byte
+	sent    []*types.Transaction
+	logs    []types.Log
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{outputs: make(map[string][]byte)}
+}
+
+// setOutput makes calls of method return the packed values.
+func (b *fakeBackend) setOutput(t *testing.T, parsed abi.ABI, method string, values ...interface{}) {
+	out, err := parsed.Methods[method].Outputs.Pack(values...)
+	assert.NoError(t, err)
+	b.outputs[string(parsed.Methods[method].ID)] = out
+}
+
+func (b *fakeBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *fakeBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	out, ok := b.outputs[string(call.Data[:4])]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return out, nil
+}
+
+func (b *fakeBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1)}, nil
+}
+
+func (b *fakeBackend) PendingCodeAt(context.Context, common.Address) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return uint64(len(b.sent)), nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *fakeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *fakeBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 100000, nil
+}
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *fakeBackend) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range b.logs {
+		if len(q.Addresses) > 0 && log.Address != q.Addresses[0] {
+			continue
+		}
+		if len(q.Topics) > 0 && len(q.Topics[0]) > 0 && log.Topics[0] != q.Topics[0][0] {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (b *fakeBackend) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func newTransactOpts(t *testing.T) *bind.TransactOpts {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	assert.NoError(t, err)
+	return opts
+}
+
+// checkCalldata checks the selector of data against the Solidity signature of the method
+// and returns the decoded arguments.
+func checkCalldata(t *testing.T, parsed abi.ABI, signature string, data []byte) []interface{} {
+	assert.Equal(t, crypto.Keccak256([]byte(signature))[:4], data[:4], signature)
+	method, err := parsed.MethodById(data[:4])
+	assert.NoError(t, err)
+	args, err := method.Inputs.Unpack(data[4:])
+	assert.NoError(t, err)
+	return args
+}
+
+// eventLog builds the log of an event emitted at address, indexed values first.
+func eventLog(t *testing.T, parsed abi.ABI, name string, address common.Address, indexed []common.Hash, values ...interface{}) types.Log {
+	ev := parsed.Events[name]
+	data, err := ev.Inputs.NonIndexed().Pack(values...)
+	assert.NoError(t, err)
+	return types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{ev.ID}, indexed...),
+		Data:        data,
+		BlockNumber: 7,
+	}
+}
+
+// assertSameValues compares values by their printed form, big.Int zeros decoded by the abi
+// package differ from big.NewInt(0) in their internal representation.
+func assertSameValues(t *testing.T, expected interface{}, actual interface{}) {
+	assert.Equal(t, fmt.Sprintf("%+v", expected), fmt.Sprintf("%+v", actual))
+}
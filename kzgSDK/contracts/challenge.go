@@ -0,0 +1,154 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// ChallengeContract is a binding of src/ChallengeContract.sol.
+type ChallengeContract struct {
+	boundContract
+}
+
+// NewChallengeContract binds the ChallengeContract deployed at address.
+func NewChallengeContract(address common.Address, backend bind.ContractBackend) *ChallengeContract {
+	return &ChallengeContract{newBoundContract(address, ChallengeContractABI, backend)}
+}
+
+// CreateChallenge challenges storageAddr over the commitments [start, end] of a namespace.
+func (c *ChallengeContract) CreateChallenge(
+	opts *bind.TransactOpts,
+	start *big.Int,
+	end *big.Int,
+	storageAddr common.Address,
+	r *big.Int,
+	point *big.Int,
+	nameSpaceKey [32]byte,
+) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "createChallenge", start, end, storageAddr, r, point, nameSpaceKey)
+}
+
+// SubmitAggregateCommitment submits the aggregate commitment requested by the current state of a challenge.
+func (c *ChallengeContract) SubmitAggregateCommitment(opts *bind.TransactOpts, challengeId *big.Int, commitment G1Point) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "submitAggregateCommitment", challengeId, commitment)
+}
+
+// SubmitOpinion agrees or disagrees with the last aggregate commitment of a challenge.
+func (c *ChallengeContract) SubmitOpinion(opts *bind.TransactOpts, challengeId *big.Int, agreed bool) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "submitOpinion", challengeId, agreed)
+}
+
+// UploadProof uploads the opening proof of the aggregate commitment of a challenge at its point.
+func (c *ChallengeContract) UploadProof(opts *bind.TransactOpts, challengeId *big.Int, proof G1Point, value *big.Int) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "uploadProof", challengeId, proof, value)
+}
+
+// Challenges returns the challenge of the given nonce.
+func (c *ChallengeContract) Challenges(opts *bind.CallOpts, challengeId *big.Int) (Challenge, error) {
+	out, err := c.call(opts, "challenges", challengeId)
+	if err != nil {
+		return Challenge{}, err
+	}
+	return Challenge{
+		Nonce:               *abi.ConvertType(out[0], new(*big.Int)).(**big.Int),
+		Status:              ChallengeStatus(*abi.ConvertType(out[1], new(uint8)).(*uint8)),
+		Challenger:          *abi.ConvertType(out[2], new(common.Address)).(*common.Address),
+		StorageAddr:         *abi.ConvertType(out[3], new(common.Address)).(*common.Address),
+		NameSpaceKey:        *abi.ConvertType(out[4], new([32]byte)).(*[32]byte),
+		Start:               *abi.ConvertType(out[5], new(*big.Int)).(**big.Int),
+		End:                 *abi.ConvertType(out[6], new(*big.Int)).(**big.Int),
+		R:                   *abi.ConvertType(out[7], new(*big.Int)).(**big.Int),
+		AggregateCommitment: *abi.ConvertType(out[8], new(G1Point)).(*G1Point),
+		Point:               *abi.ConvertType(out[9], new(*big.Int)).(**big.Int),
+		TimeoutBlock:        *abi.ConvertType(out[10], new(*big.Int)).(**big.Int),
+	}, nil
+}
+
+// ChallengeDetails returns the bisection state of the challenge of the given nonce.
+func (c *ChallengeContract) ChallengeDetails(opts *bind.CallOpts, challengeId *big.Int) (ChallengeDetails, error) {
+	out, err := c.call(opts, "challengeDetailsMap", challengeId)
+	if err != nil {
+		return ChallengeDetails{}, err
+	}
+	return ChallengeDetails{
+		ConsensusIndex:          *abi.ConvertType(out[0], new(*big.Int)).(**big.Int),
+		NoConsensusIndex:        *abi.ConvertType(out[1], new(*big.Int)).(**big.Int),
+		CurrentIndex:            *abi.ConvertType(out[2], new(*big.Int)).(**big.Int),
+		ConsensusCommitment:     *abi.ConvertType(out[3], new(G1Point)).(*G1Point),
+		NoConsensusCommitment:   *abi.ConvertType(out[4], new(G1Point)).(*G1Point),
+		CurrAggregateCommitment: *abi.ConvertType(out[5], new(G1Point)).(*G1Point),
+	}, nil
+}
+
+// Nonce returns the nonce of the next challenge.
+func (c *ChallengeContract) Nonce(opts *bind.CallOpts) (*big.Int, error) {
+	return c.callBigInt(opts, "nonce")
+}
+
+// CommitmentManager returns the address of the CommitmentManager holding the challenged commitments.
+func (c *ChallengeContract) CommitmentManager(opts *bind.CallOpts) (common.Address, error) {
+	return c.callAddress(opts, "commitmentManager")
+}
+
+// ChallengeCreated is the ChallengeCreated event of ChallengeContract.
+type ChallengeCreated struct {
+	Nonce        *big.Int
+	StorageAddr  common.Address
+	NameSpaceKey [32]byte
+	Start        *big.Int
+	End          *big.Int
+	R            *big.Int
+	TimeoutBlock *big.Int
+	Raw          types.Log
+}
+
+// ParseChallengeCreated decodes a ChallengeCreated log.
+func (c *ChallengeContract) ParseChallengeCreated(log types.Log) (*ChallengeCreated, error) {
+	ev := &ChallengeCreated{Raw: log}
+	if err := unpackLog(&c.boundContract, ev, "ChallengeCreated", log); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FilterChallengeCreated returns the ChallengeCreated events in the block range of opts.
+func (c *ChallengeContract) FilterChallengeCreated(opts *bind.FilterOpts) ([]*ChallengeCreated, error) {
+	return filterEvents(&c.boundContract, opts, "ChallengeCreated", c.ParseChallengeCreated)
+}
+
+// WatchChallengeCreated sends the new ChallengeCreated events to sink.
+func (c *ChallengeContract) WatchChallengeCreated(opts *bind.WatchOpts, sink chan<- *ChallengeCreated) (event.Subscription, error) {
+	return watchEvents(&c.boundContract, opts, "ChallengeCreated", sink, c.ParseChallengeCreated)
+}
+
+// AggregateCommitmentSubmit is the AggregateCommitmentSubmit event of ChallengeContract.
+type AggregateCommitmentSubmit struct {
+	Nonce               *big.Int
+	AggregateCommitment G1Point
+	TimeoutBlock        *big.Int
+	Raw                 types.Log
+}
+
+// ParseAggregateCommitmentSubmit decodes an AggregateCommitmentSubmit log.
+func (c *ChallengeContract) ParseAggregateCommitmentSubmit(log types.Log) (*AggregateCommitmentSubmit, error) {
+	ev := &AggregateCommitmentSubmit{Raw: log}
+	if err := unpackLog(&c.boundContract, ev, "AggregateCommitmentSubmit", log); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FilterAggregateCommitmentSubmit returns the AggregateCommitmentSubmit events in the block range of opts.
+func (c *ChallengeContract) FilterAggregateCommitmentSubmit(opts *bind.FilterOpts) ([]*AggregateCommitmentSubmit, error) {
+	return filterEvents(&c.boundContract, opts, "AggregateCommitmentSubmit", c.ParseAggregateCommitmentSubmit)
+}
+
+// WatchAggregateCommitmentSubmit sends the new AggregateCommitmentSubmit events to sink.
+func (c *ChallengeContract) WatchAggregateCommitmentSubmit(opts *bind.WatchOpts, sink chan<- *AggregateCommitmentSubmit) (event.Subscription, error) {
+	return watchEvents(&c.boundContract, opts, "AggregateCommitmentSubmit", sink, c.ParseAggregateCommitmentSubmit)
+}
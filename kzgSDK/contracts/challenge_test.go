@@ -0,0 +1,137 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	challengeAddress = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	storageAddress   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	nameSpaceKey     = common.HexToHash("0x6e616d657370616365")
+)
+
+func TestChallengeContractTransactions(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewChallengeContract(challengeAddress, backend)
+	opts := newTransactOpts(t)
+	point := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+
+	tx, err := contract.CreateChallenge(opts, big.NewInt(3), big.NewInt(9), storageAddress, big.NewInt(11), big.NewInt(13), nameSpaceKey)
+	assert.NoError(t, err)
+	assert.Equal(t, challengeAddress, *tx.To())
+	args := checkCalldata(t, ChallengeContractABI, "createChallenge(uint256,uint256,address,uint256,uint256,bytes32)", tx.Data())
+	assert.Equal(t, []interface{}{big.NewInt(3), big.NewInt(9), storageAddress, big.NewInt(11), big.NewInt(13), [32]byte(nameSpaceKey)}, args)
+
+	tx, err = contract.SubmitAggregateCommitment(opts, big.NewInt(4), point)
+	assert.NoError(t, err)
+	args = checkCalldata(t, ChallengeContractABI, "submitAggregateCommitment(uint256,(uint256,uint256))", tx.Data())
+	assert.Equal(t, big.NewInt(4), args[0])
+
+	tx, err = contract.SubmitOpinion(opts, big.NewInt(4), true)
+	assert.NoError(t, err)
+	args = checkCalldata(t, ChallengeContractABI, "submitOpinion(uint256,bool)", tx.Data())
+	assert.Equal(t, []interface{}{big.NewInt(4), true}, args)
+
+	tx, err = contract.UploadProof(opts, big.NewInt(4), point, big.NewInt(42))
+	assert.NoError(t, err)
+	args = checkCalldata(t, ChallengeContractABI, "uploadProof(uint256,(uint256,uint256),uint256)", tx.Data())
+	assert.Equal(t, big.NewInt(42), args[2])
+
+	assert.Equal(t, 4, len(backend.sent))
+}
+
+func TestChallengeContractCalls(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewChallengeContract(challengeAddress, backend)
+	aggregate := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+	zero := G1Point{X: big.NewInt(0), Y: big.NewInt(0)}
+
+	backend.setOutput(t, ChallengeContractABI, "challenges",
+		big.NewInt(4), uint8(StatusCommitNotAgreed), common.Address{1}, storageAddress, [32]byte(nameSpaceKey),
+		big.NewInt(3), big.NewInt(9), big.NewInt(11), aggregate, big.NewInt(13), big.NewInt(700))
+	challenge, err := contract.Challenges(&bind.CallOpts{}, big.NewInt(4))
+	assert.NoError(t, err)
+	assert.Equal(t, Challenge{
+		Nonce:               big.NewInt(4),
+		Status:              StatusCommitNotAgreed,
+		Challenger:          common.Address{1},
+		StorageAddr:         storageAddress,
+		NameSpaceKey:        nameSpaceKey,
+		Start:               big.NewInt(3),
+		End:                 big.NewInt(9),
+		R:                   big.NewInt(11),
+		AggregateCommitment: aggregate,
+		Point:               big.NewInt(13),
+		TimeoutBlock:        big.NewInt(700),
+	}, challenge)
+
+	backend.setOutput(t, ChallengeContractABI, "challengeDetailsMap",
+		big.NewInt(3), big.NewInt(9), big.NewInt(6), zero, aggregate, zero)
+	details, err := contract.ChallengeDetails(&bind.CallOpts{}, big.NewInt(4))
+	assert.NoError(t, err)
+	assertSameValues(t, ChallengeDetails{
+		ConsensusIndex:          big.NewInt(3),
+		NoConsensusIndex:        big.NewInt(9),
+		CurrentIndex:            big.NewInt(6),
+		ConsensusCommitment:     zero,
+		NoConsensusCommitment:   aggregate,
+		CurrAggregateCommitment: zero,
+	}, details)
+
+	backend.setOutput(t, ChallengeContractABI, "nonce", big.NewInt(5))
+	nonce, err := contract.Nonce(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), nonce)
+
+	_, err = contract.CommitmentManager(nil)
+	assert.Error(t, err)
+}
+
+func TestChallengeContractEvents(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewChallengeContract(challengeAddress, backend)
+	aggregate := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+
+	created := eventLog(t, ChallengeContractABI, "ChallengeCreated", challengeAddress, nil,
+		big.NewInt(4), storageAddress, [32]byte(nameSpaceKey), big.NewInt(3), big.NewInt(9), big.NewInt(11), big.NewInt(607))
+	assert.Equal(t, "ChallengeCreated(uint256,address,bytes32,uint256,uint256,uint256,uint256)", ChallengeContractABI.Events["ChallengeCreated"].Sig)
+	ev, err := contract.ParseChallengeCreated(created)
+	assert.NoError(t, err)
+	assert.Equal(t, &ChallengeCreated{
+		Nonce:        big.NewInt(4),
+		StorageAddr:  storageAddress,
+		NameSpaceKey: nameSpaceKey,
+		Start:        big.NewInt(3),
+		End:          big.NewInt(9),
+		R:            big.NewInt(11),
+		TimeoutBlock: big.NewInt(607),
+		Raw:          created,
+	}, ev)
+
+	submitted := eventLog(t, ChallengeContractABI, "AggregateCommitmentSubmit", challengeAddress, nil,
+		big.NewInt(4), aggregate, big.NewInt(610))
+	assert.Equal(t, "AggregateCommitmentSubmit(uint256,(uint256,uint256),uint256)", ChallengeContractABI.Events["AggregateCommitmentSubmit"].Sig)
+	sev, err := contract.ParseAggregateCommitmentSubmit(submitted)
+	assert.NoError(t, err)
+	assert.Equal(t, aggregate, sev.AggregateCommitment)
+	assert.Equal(t, big.NewInt(610), sev.TimeoutBlock)
+
+	// logs of another event or contract are rejected
+	_, err = contract.ParseChallengeCreated(submitted)
+	assert.Error(t, err)
+	other := created
+	other.Address = storageAddress
+	_, err = contract.ParseChallengeCreated(other)
+	assert.ErrorIs(t, err, ErrUnexpectedLog)
+
+	backend.logs = []types.Log{created, submitted, other}
+	events, err := contract.FilterChallengeCreated(&bind.FilterOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*ChallengeCreated{ev}, events)
+}
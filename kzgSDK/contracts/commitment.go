@@ -0,0 +1,118 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// CommitmentManager is a binding of src/CommitmentManager.sol.
+type CommitmentManager struct {
+	boundContract
+}
+
+// NewCommitmentManager binds the CommitmentManager deployed at address.
+func NewCommitmentManager(address common.Address, backend bind.ContractBackend) *CommitmentManager {
+	return &CommitmentManager{newBoundContract(address, CommitmentManagerABI, backend)}
+}
+
+// PackSubmitCommitment returns the calldata of submitCommitment, signatures[i] being the signature
+// of the i-th address of the sorted node group.
+func PackSubmitCommitment(
+	length *big.Int,
+	timeout *big.Int,
+	nameSpaceKey [32]byte,
+	nodeGroupKey [32]byte,
+	signatures [][]byte,
+	commitment G1Point,
+) ([]byte, error) {
+	return CommitmentManagerABI.Pack("submitCommitment", length, timeout, nameSpaceKey, nodeGroupKey, signatures, commitment)
+}
+
+// SubmitCommitment submits a DA commitment signed by the broadcast nodes of a node group,
+// opts.Value must cover baseFee*length.
+func (c *CommitmentManager) SubmitCommitment(
+	opts *bind.TransactOpts,
+	length *big.Int,
+	timeout *big.Int,
+	nameSpaceKey [32]byte,
+	nodeGroupKey [32]byte,
+	signatures [][]byte,
+	commitment G1Point,
+) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "submitCommitment", length, timeout, nameSpaceKey, nodeGroupKey, signatures, commitment)
+}
+
+// BaseFee returns the fee per byte of data.
+func (c *CommitmentManager) BaseFee(opts *bind.CallOpts) (*big.Int, error) {
+	return c.callBigInt(opts, "baseFee")
+}
+
+// Nonce returns the nonce of the next commitment.
+func (c *CommitmentManager) Nonce(opts *bind.CallOpts) (*big.Int, error) {
+	return c.callBigInt(opts, "nonce")
+}
+
+// Indices returns the index of the next commitment submitted by user, the index signed by the broadcast nodes.
+func (c *CommitmentManager) Indices(opts *bind.CallOpts, user common.Address) (*big.Int, error) {
+	return c.callBigInt(opts, "indices", user)
+}
+
+// NameSpaceIndex returns the number of commitments of a namespace, the index of the next one.
+func (c *CommitmentManager) NameSpaceIndex(opts *bind.CallOpts, nameSpaceKey [32]byte) (*big.Int, error) {
+	return c.callBigInt(opts, "nameSpaceIndex", nameSpaceKey)
+}
+
+// GetNameSpaceCommitment returns the commitment at index of a namespace.
+func (c *CommitmentManager) GetNameSpaceCommitment(opts *bind.CallOpts, nameSpaceKey [32]byte, index *big.Int) (G1Point, error) {
+	out, err := c.call(opts, "getNameSpaceCommitment", nameSpaceKey, index)
+	if err != nil {
+		return G1Point{}, err
+	}
+	return *abi.ConvertType(out[0], new(G1Point)).(*G1Point), nil
+}
+
+// GetUserCommitment returns the commitment at index of a user.
+func (c *CommitmentManager) GetUserCommitment(opts *bind.CallOpts, user common.Address, index *big.Int) (G1Point, error) {
+	out, err := c.call(opts, "getUserCommitment", user, index)
+	if err != nil {
+		return G1Point{}, err
+	}
+	return *abi.ConvertType(out[0], new(G1Point)).(*G1Point), nil
+}
+
+// SendDACommitment is the SendDACommitment event of CommitmentManager.
+type SendDACommitment struct {
+	Commitment   G1Point
+	Timestamp    *big.Int
+	Nonce        *big.Int
+	Index        *big.Int
+	Timeout      *big.Int
+	NodeGroupKey [32]byte
+	NameSpaceKey [32]byte
+	Signatures   [][]byte
+	Raw          types.Log
+}
+
+// ParseSendDACommitment decodes a SendDACommitment log.
+func (c *CommitmentManager) ParseSendDACommitment(log types.Log) (*SendDACommitment, error) {
+	ev := &SendDACommitment{Raw: log}
+	if err := unpackLog(&c.boundContract, ev, "SendDACommitment", log); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FilterSendDACommitment returns the SendDACommitment events in the block range of opts.
+func (c *CommitmentManager) FilterSendDACommitment(opts *bind.FilterOpts) ([]*SendDACommitment, error) {
+	return filterEvents(&c.boundContract, opts, "SendDACommitment", c.ParseSendDACommitment)
+}
+
+// WatchSendDACommitment sends the new SendDACommitment events to sink.
+func (c *CommitmentManager) WatchSendDACommitment(opts *bind.WatchOpts, sink chan<- *SendDACommitment) (event.Subscription, error) {
+	return watchEvents(&c.boundContract, opts, "SendDACommitment", sink, c.ParseSendDACommitment)
+}
@@ -0,0 +1,95 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var commitmentAddress = common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+func TestSubmitCommitment(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewCommitmentManager(commitmentAddress, backend)
+	opts := newTransactOpts(t)
+	opts.Value = big.NewInt(3000)
+	commitment := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+	signatures := [][]byte{make([]byte, 65), {1, 2, 3}}
+	nodeGroupKey := common.HexToHash("0x6e6f646567726f7570")
+
+	tx, err := contract.SubmitCommitment(opts, big.NewInt(300), big.NewInt(1700000000), nameSpaceKey, nodeGroupKey, signatures, commitment)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3000), tx.Value())
+	args := checkCalldata(t, CommitmentManagerABI, "submitCommitment(uint256,uint256,bytes32,bytes32,bytes[],(uint256,uint256))", tx.Data())
+	assert.Equal(t, big.NewInt(300), args[0])
+	assert.Equal(t, [32]byte(nameSpaceKey), args[2])
+	assert.Equal(t, [32]byte(nodeGroupKey), args[3])
+	assert.Equal(t, signatures, args[4])
+
+	calldata, err := PackSubmitCommitment(big.NewInt(300), big.NewInt(1700000000), nameSpaceKey, nodeGroupKey, signatures, commitment)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Data(), calldata)
+}
+
+func TestCommitmentManagerCalls(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewCommitmentManager(commitmentAddress, backend)
+	commitment := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+
+	backend.setOutput(t, CommitmentManagerABI, "baseFee", big.NewInt(10))
+	backend.setOutput(t, CommitmentManagerABI, "indices", big.NewInt(2))
+	backend.setOutput(t, CommitmentManagerABI, "nameSpaceIndex", big.NewInt(80))
+	backend.setOutput(t, CommitmentManagerABI, "getNameSpaceCommitment", commitment)
+
+	fee, err := contract.BaseFee(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), fee)
+	index, err := contract.Indices(nil, common.Address{1})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), index)
+	count, err := contract.NameSpaceIndex(nil, nameSpaceKey)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(80), count)
+	got, err := contract.GetNameSpaceCommitment(nil, nameSpaceKey, big.NewInt(79))
+	assert.NoError(t, err)
+	assert.Equal(t, commitment, got)
+
+	_, err = contract.GetUserCommitment(nil, common.Address{1}, big.NewInt(0))
+	assert.Error(t, err)
+}
+
+func TestSendDACommitmentEvent(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewCommitmentManager(commitmentAddress, backend)
+	commitment := G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+	nodeGroupKey := common.HexToHash("0x6e6f646567726f7570")
+	signatures := [][]byte{make([]byte, 65), make([]byte, 65)}
+
+	assert.Equal(t, "SendDACommitment((uint256,uint256),uint256,uint256,uint256,uint256,bytes32,bytes32,bytes[])",
+		CommitmentManagerABI.Events["SendDACommitment"].Sig)
+	log := eventLog(t, CommitmentManagerABI, "SendDACommitment", commitmentAddress, nil,
+		commitment, big.NewInt(1700000000), big.NewInt(12), big.NewInt(3), big.NewInt(1700000600),
+		[32]byte(nodeGroupKey), [32]byte(nameSpaceKey), signatures)
+	ev, err := contract.ParseSendDACommitment(log)
+	assert.NoError(t, err)
+	assert.Equal(t, &SendDACommitment{
+		Commitment:   commitment,
+		Timestamp:    big.NewInt(1700000000),
+		Nonce:        big.NewInt(12),
+		Index:        big.NewInt(3),
+		Timeout:      big.NewInt(1700000600),
+		NodeGroupKey: nodeGroupKey,
+		NameSpaceKey: nameSpaceKey,
+		Signatures:   signatures,
+		Raw:          log,
+	}, ev)
+
+	backend.logs = []types.Log{log, log}
+	events, err := contract.FilterSendDACommitment(&bind.FilterOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(events))
+}
@@ -0,0 +1,144 @@
+package contracts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrNotBroadcastNode is returned for a node group address that is not a broadcast node.
+	ErrNotBroadcastNode = errors.New("not a broadcast node")
+	// ErrNotStorageNode is returned for a namespace address that is not a storage node.
+	ErrNotStorageNode = errors.New("not a storage node")
+	// ErrTooManyRequiredSignatures is returned for a node group requiring more signatures than it has nodes.
+	ErrTooManyRequiredSignatures = errors.New("more required signatures than nodes")
+)
+
+// SortAddresses returns a sorted copy of addresses, as StorageManager.sortAddresses sorts them:
+// in ascending order of their uint160 value, which is the order of their bytes, duplicates kept.
+func SortAddresses(addresses []common.Address) []common.Address {
+	sorted := append([]common.Address(nil), addresses...)
+	for i := 1; i < len(sorted); i++ {
+		key := sorted[i]
+		j := i - 1
+		for j >= 0 && bytes.Compare(sorted[j][:], key[:]) > 0 {
+			sorted[j+1] = sorted[j]
+			j--
+		}
+		sorted[j+1] = key
+	}
+	return sorted
+}
+
+// NodeGroupKey returns the key registerNodeGroup stores a node group under,
+// keccak256(abi.encode(required, sortAddresses(nodeAddresses))). Note that getNodeGroupKey
+// hashes the addresses as given, so it only returns this key for sorted addresses.
+func NodeGroupKey(required *big.Int, nodeAddresses []common.Address) ([32]byte, error) {
+	if required == nil || required.Sign() < 0 || required.BitLen() > 256 {
+		return [32]byte{}, fmt.Errorf("required amount of signatures %v is not a uint256", required)
+	}
+	return hashAddresses(common.LeftPadBytes(required.Bytes(), 32), SortAddresses(nodeAddresses)), nil
+}
+
+// NameSpaceKey returns the key registerNameSpace stores the namespace of creator under,
+// keccak256(abi.encode(creator, sortAddresses(nodeAddresses))). As for NodeGroupKey,
+// getNameSpaceKey only returns this key for sorted addresses.
+func NameSpaceKey(creator common.Address, nodeAddresses []common.Address) [32]byte {
+	return hashAddresses(common.LeftPadBytes(creator[:], 32), SortAddresses(nodeAddresses))
+}
+
+// hashAddresses is Hashing.hashAddresses, keccak256(abi.encode(first, addresses)) with first
+// an encoded static word.
+func hashAddresses(first []byte, addresses []common.Address) [32]byte {
+	data := make([]byte, 0, (3+len(addresses))*32)
+	data = append(data, first...)
+	// offset of the array, then its length and elements
+	data = append(data, common.LeftPadBytes(big.NewInt(64).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(addresses))).Bytes(), 32)...)
+	for _, addr := range addresses {
+		data = append(data, common.LeftPadBytes(addr[:], 32)...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// NodeSnapshot is the set of nodes registered in a NodeManager at some block, to check node
+// groups and namespaces without a call per address.
+//
+// A registered node with no stake is neither a broadcast nor a storage node for the contract.
+// NodeManager also records the NodeInfo of a node under info.addr, whatever the sender: nodes
+// recorded by a sender that was already registered are not listed, and are not in the snapshot.
+type NodeSnapshot struct {
+	broadcast map[common.Address]NodeInfo
+	storage   map[common.Address]NodeInfo
+}
+
+// NewNodeSnapshot returns the snapshot of the nodes returned by getBroadcastingNodes and getStorageNodes.
+func NewNodeSnapshot(broadcast []NodeInfo, storage []NodeInfo) *NodeSnapshot {
+	s := &NodeSnapshot{
+		broadcast: make(map[common.Address]NodeInfo, len(broadcast)),
+		storage:   make(map[common.Address]NodeInfo, len(storage)),
+	}
+	for _, info := range broadcast {
+		s.broadcast[info.Addr] = info
+	}
+	for _, info := range storage {
+		s.storage[info.Addr] = info
+	}
+	return s
+}
+
+// LoadNodeSnapshot reads the nodes of a NodeManager.
+func LoadNodeSnapshot(opts *bind.CallOpts, nodeManager *NodeManager) (*NodeSnapshot, error) {
+	broadcast, err := nodeManager.GetBroadcastingNodes(opts)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := nodeManager.GetStorageNodes(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewNodeSnapshot(broadcast, storage), nil
+}
+
+// IsNodeBroadcast mirrors NodeManager.isNodeBroadcast.
+func (s *NodeSnapshot) IsNodeBroadcast(addr common.Address) bool {
+	info, ok := s.broadcast[addr]
+	return ok && info.StakedTokens != nil && info.StakedTokens.Sign() != 0
+}
+
+// IsNodeStorage mirrors NodeManager.isNodeStorage.
+func (s *NodeSnapshot) IsNodeStorage(addr common.Address) bool {
+	info, ok := s.storage[addr]
+	return ok && info.StakedTokens != nil && info.StakedTokens.Sign() != 0
+}
+
+// CheckNodeGroup runs the checks of registerNodeGroup, returning ErrTooManyRequiredSignatures
+// or ErrNotBroadcastNode if the registration would revert.
+func (s *NodeSnapshot) CheckNodeGroup(required *big.Int, nodeAddresses []common.Address) error {
+	if required == nil || required.Cmp(big.NewInt(int64(len(nodeAddresses)))) > 0 {
+		return fmt.Errorf("%w: %v of %d", ErrTooManyRequiredSignatures, required, len(nodeAddresses))
+	}
+	for _, addr := range nodeAddresses {
+		if !s.IsNodeBroadcast(addr) {
+			return fmt.Errorf("%w: %s", ErrNotBroadcastNode, addr)
+		}
+	}
+	return nil
+}
+
+// CheckNameSpace runs the checks of registerNameSpace, returning ErrNotStorageNode if the
+// registration would revert.
+func (s *NodeSnapshot) CheckNameSpace(nodeAddresses []common.Address) error {
+	for _, addr := range nodeAddresses {
+		if !s.IsNodeStorage(addr) {
+			return fmt.Errorf("%w: %s", ErrNotStorageNode, addr)
+		}
+	}
+	return nil
+}
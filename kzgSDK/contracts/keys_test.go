@@ -0,0 +1,124 @@
+package contracts
+
+import (
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyVectors are the vectors of test/fixtures/storage-keys.json, checked by test/StorageManager.t.sol.
+type keyVectors struct {
+	Creator common.Address `json:"creator"`
+	Vectors []struct {
+		Required     int64            `json:"required"`
+		Addresses    []common.Address `json:"addresses"`
+		Sorted       []common.Address `json:"sorted"`
+		NodeGroupKey common.Hash      `json:"nodeGroupKey"`
+		NameSpaceKey common.Hash      `json:"nameSpaceKey"`
+	} `json:"vectors"`
+}
+
+// abiHashAddresses hashes with the abi package rather than the encoding of hashAddresses.
+func abiHashAddresses(t *testing.T, firstType string, first interface{}, addresses []common.Address) [32]byte {
+	newType := func(name string) abi.Type {
+		typ, err := abi.NewType(name, "", nil)
+		require.NoError(t, err)
+		return typ
+	}
+	encoded, err := abi.Arguments{{Type: newType(firstType)}, {Type: newType("address[]")}}.Pack(first, addresses)
+	require.NoError(t, err)
+	return crypto.Keccak256Hash(encoded)
+}
+
+func TestKeyVectors(t *testing.T) {
+	data, err := os.ReadFile("../../test/fixtures/storage-keys.json")
+	require.NoError(t, err)
+	var vectors keyVectors
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors.Vectors)
+
+	for i, v := range vectors.Vectors {
+		assert.Equal(t, v.Sorted, SortAddresses(v.Addresses), "vector %d", i)
+		key, err := NodeGroupKey(big.NewInt(v.Required), v.Addresses)
+		require.NoError(t, err)
+		assert.Equal(t, [32]byte(v.NodeGroupKey), key, "vector %d", i)
+		assert.Equal(t, abiHashAddresses(t, "uint256", big.NewInt(v.Required), v.Sorted), key, "vector %d", i)
+		assert.Equal(t, [32]byte(v.NameSpaceKey), NameSpaceKey(vectors.Creator, v.Addresses), "vector %d", i)
+		assert.Equal(t, abiHashAddresses(t, "address", vectors.Creator, v.Sorted), NameSpaceKey(vectors.Creator, v.Addresses), "vector %d", i)
+	}
+}
+
+func TestSortAddresses(t *testing.T) {
+	assert.Empty(t, SortAddresses(nil))
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 20; n++ {
+		addresses := make([]common.Address, n)
+		for i := range addresses {
+			// few distinct leading bytes, so that the order is decided further in
+			addresses[i][0] = byte(r.Intn(3))
+			r.Read(addresses[i][1:])
+			if i > 0 && r.Intn(4) == 0 {
+				addresses[i] = addresses[r.Intn(i)]
+			}
+		}
+		original := slices.Clone(addresses)
+		sorted := SortAddresses(addresses)
+		assert.Equal(t, original, addresses, "the input is not modified")
+		assert.ElementsMatch(t, addresses, sorted)
+		for i := 1; i < len(sorted); i++ {
+			assert.True(t, new(big.Int).SetBytes(sorted[i-1][:]).Cmp(new(big.Int).SetBytes(sorted[i][:])) <= 0)
+		}
+		// the key does not depend on the order of the addresses
+		r.Shuffle(len(original), func(i, j int) { original[i], original[j] = original[j], original[i] })
+		assert.Equal(t, NameSpaceKey(common.Address{1}, addresses), NameSpaceKey(common.Address{1}, original))
+	}
+
+	key, err := NodeGroupKey(big.NewInt(0), nil)
+	require.NoError(t, err)
+	assert.Equal(t, abiHashAddresses(t, "uint256", big.NewInt(0), []common.Address{}), key)
+	_, err = NodeGroupKey(big.NewInt(-1), nil)
+	assert.Error(t, err)
+	_, err = NodeGroupKey(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestNodeSnapshot(t *testing.T) {
+	node := func(addr byte, stake int64) NodeInfo {
+		return NodeInfo{StakedTokens: big.NewInt(stake), MaxStorageSpace: big.NewInt(1), Addr: common.Address{addr}}
+	}
+	snapshot := NewNodeSnapshot(
+		[]NodeInfo{node(1, 100), node(2, 100), node(3, 0)},
+		[]NodeInfo{node(4, 100), node(5, 1)},
+	)
+	assert.True(t, snapshot.IsNodeBroadcast(common.Address{1}))
+	assert.False(t, snapshot.IsNodeBroadcast(common.Address{3}), "no stake")
+	assert.False(t, snapshot.IsNodeBroadcast(common.Address{4}))
+	assert.True(t, snapshot.IsNodeStorage(common.Address{5}))
+	assert.False(t, snapshot.IsNodeStorage(common.Address{1}))
+
+	assert.NoError(t, snapshot.CheckNodeGroup(big.NewInt(2), []common.Address{{2}, {1}}))
+	assert.NoError(t, snapshot.CheckNodeGroup(big.NewInt(0), nil))
+	assert.ErrorIs(t, snapshot.CheckNodeGroup(big.NewInt(3), []common.Address{{2}, {1}}), ErrTooManyRequiredSignatures)
+	assert.ErrorIs(t, snapshot.CheckNodeGroup(big.NewInt(1), []common.Address{{1}, {3}}), ErrNotBroadcastNode)
+	assert.ErrorIs(t, snapshot.CheckNodeGroup(big.NewInt(1), []common.Address{{4}}), ErrNotBroadcastNode)
+	assert.NoError(t, snapshot.CheckNameSpace([]common.Address{{5}, {4}, {5}}))
+	assert.ErrorIs(t, snapshot.CheckNameSpace([]common.Address{{4}, {1}}), ErrNotStorageNode)
+
+	backend := newFakeBackend()
+	backend.setOutput(t, NodeManagerABI, "getBroadcastingNodes", []NodeInfo{node(1, 100)})
+	backend.setOutput(t, NodeManagerABI, "getStorageNodes", []NodeInfo{node(4, 100)})
+	loaded, err := LoadNodeSnapshot(nil, NewNodeManager(nodeManagerAddress, backend))
+	require.NoError(t, err)
+	assert.True(t, loaded.IsNodeBroadcast(common.Address{1}))
+	assert.True(t, loaded.IsNodeStorage(common.Address{4}))
+	assert.False(t, loaded.IsNodeStorage(common.Address{1}))
+}
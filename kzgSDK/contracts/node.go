@@ -0,0 +1,64 @@
+package contracts
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NodeManager is a binding of src/NodeManager.sol.
+type NodeManager struct {
+	boundContract
+}
+
+// NewNodeManager binds the NodeManager deployed at address.
+func NewNodeManager(address common.Address, backend bind.ContractBackend) *NodeManager {
+	return &NodeManager{newBoundContract(address, NodeManagerABI, backend)}
+}
+
+// RegisterBroadcastNode registers, or updates, the sender as a broadcast node.
+func (c *NodeManager) RegisterBroadcastNode(opts *bind.TransactOpts, info NodeInfo) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "registerBroadcastNode", info)
+}
+
+// RegisterStorageNode registers, or updates, the sender as a storage node.
+func (c *NodeManager) RegisterStorageNode(opts *bind.TransactOpts, info NodeInfo) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "registerStorageNode", info)
+}
+
+// GetBroadcastingNodes returns the broadcast nodes, in registration order.
+func (c *NodeManager) GetBroadcastingNodes(opts *bind.CallOpts) ([]NodeInfo, error) {
+	return c.callNodes(opts, "getBroadcastingNodes")
+}
+
+// GetStorageNodes returns the storage nodes, in registration order.
+func (c *NodeManager) GetStorageNodes(opts *bind.CallOpts) ([]NodeInfo, error) {
+	return c.callNodes(opts, "getStorageNodes")
+}
+
+// IsNodeBroadcast reports whether addr is a broadcast node with a non-zero stake.
+func (c *NodeManager) IsNodeBroadcast(opts *bind.CallOpts, addr common.Address) (bool, error) {
+	return c.callBool(opts, "isNodeBroadcast", addr)
+}
+
+// IsNodeStorage reports whether addr is a storage node with a non-zero stake.
+func (c *NodeManager) IsNodeStorage(opts *bind.CallOpts, addr common.Address) (bool, error) {
+	return c.callBool(opts, "isNodeStorage", addr)
+}
+
+func (c *NodeManager) callNodes(opts *bind.CallOpts, method string) ([]NodeInfo, error) {
+	out, err := c.call(opts, method)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]NodeInfo)).(*[]NodeInfo), nil
+}
+
+func (c *NodeManager) callBool(opts *bind.CallOpts, method string, params ...interface{}) (bool, error) {
+	out, err := c.call(opts, method, params...)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
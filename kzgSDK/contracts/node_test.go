@@ -0,0 +1,35 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var nodeManagerAddress = common.HexToAddress("0x5000000000000000000000000000000000000005")
+
+func TestNodeManager(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewNodeManager(nodeManagerAddress, backend)
+	nodes := []NodeInfo{
+		{Url: "http://127.0.0.1:8545", Name: "storage-0", StakedTokens: big.NewInt(100), Location: "eu", MaxStorageSpace: big.NewInt(1 << 40), Addr: common.Address{1}},
+		{Url: "http://127.0.0.1:8546", Name: "storage-1", StakedTokens: big.NewInt(0), Location: "us", MaxStorageSpace: big.NewInt(1 << 30), Addr: common.Address{2}},
+	}
+
+	backend.setOutput(t, NodeManagerABI, "getStorageNodes", nodes)
+	got, err := contract.GetStorageNodes(nil)
+	assert.NoError(t, err)
+	assertSameValues(t, nodes, got)
+
+	backend.setOutput(t, NodeManagerABI, "isNodeBroadcast", true)
+	ok, err := contract.IsNodeBroadcast(nil, common.Address{1})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	tx, err := contract.RegisterStorageNode(newTransactOpts(t), nodes[0])
+	assert.NoError(t, err)
+	args := checkCalldata(t, NodeManagerABI, "registerStorageNode((string,string,uint256,string,uint256,address))", tx.Data())
+	assertSameValues(t, nodes[0], args[0])
+}
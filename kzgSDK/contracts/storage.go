@@ -0,0 +1,122 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// StorageManager is a binding of src/StorageManager.sol.
+type StorageManager struct {
+	boundContract
+}
+
+// NewStorageManager binds the StorageManager deployed at address.
+func NewStorageManager(address common.Address, backend bind.ContractBackend) *StorageManager {
+	return &StorageManager{newBoundContract(address, StorageManagerABI, backend)}
+}
+
+// RegisterNodeGroup registers a group of broadcast nodes of which required must sign a commitment.
+func (c *StorageManager) RegisterNodeGroup(opts *bind.TransactOpts, required *big.Int, nodeAddresses []common.Address) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "registerNodeGroup", required, nodeAddresses)
+}
+
+// RegisterNameSpace registers a namespace stored by the given storage nodes, owned by the sender.
+func (c *StorageManager) RegisterNameSpace(opts *bind.TransactOpts, nodeAddresses []common.Address) (*types.Transaction, error) {
+	return c.contract.Transact(opts, "registerNameSpace", nodeAddresses)
+}
+
+// NodeGroup returns the node group registered under key, with no addresses if there is none.
+func (c *StorageManager) NodeGroup(opts *bind.CallOpts, key [32]byte) (NodeGroup, error) {
+	out, err := c.call(opts, "NODEGROUP", key)
+	if err != nil {
+		return NodeGroup{}, err
+	}
+	return *abi.ConvertType(out[0], new(NodeGroup)).(*NodeGroup), nil
+}
+
+// NameSpace returns the namespace registered under key, with a zero creator if there is none.
+func (c *StorageManager) NameSpace(opts *bind.CallOpts, key [32]byte) (NameSpace, error) {
+	out, err := c.call(opts, "NAMESPACE", key)
+	if err != nil {
+		return NameSpace{}, err
+	}
+	return *abi.ConvertType(out[0], new(NameSpace)).(*NameSpace), nil
+}
+
+// NodeManager returns the address of the NodeManager the node addresses are checked against.
+func (c *StorageManager) NodeManager(opts *bind.CallOpts) (common.Address, error) {
+	return c.callAddress(opts, "nodeManager")
+}
+
+// NodeGroupRegistered is the NodeGroupRegistered event of StorageManager.
+// NodeAddresses are the addresses as given by the creator, before sorting.
+type NodeGroupRegistered struct {
+	Creator                    common.Address
+	Key                        [32]byte
+	RequiredAmountOfSignatures *big.Int
+	NodeAddresses              []common.Address
+	Raw                        types.Log
+}
+
+// ParseNodeGroupRegistered decodes a NodeGroupRegistered log.
+func (c *StorageManager) ParseNodeGroupRegistered(log types.Log) (*NodeGroupRegistered, error) {
+	ev := &NodeGroupRegistered{Raw: log}
+	if err := unpackLog(&c.boundContract, ev, "NodeGroupRegistered", log); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FilterNodeGroupRegistered returns the NodeGroupRegistered events in the block range of opts,
+// restricted to the given creators and keys when they are not empty.
+func (c *StorageManager) FilterNodeGroupRegistered(opts *bind.FilterOpts, creator []common.Address, key [][32]byte) ([]*NodeGroupRegistered, error) {
+	return filterEvents(&c.boundContract, opts, "NodeGroupRegistered", c.ParseNodeGroupRegistered, toQuery(creator), toQuery(key))
+}
+
+// WatchNodeGroupRegistered sends the new NodeGroupRegistered events to sink.
+func (c *StorageManager) WatchNodeGroupRegistered(opts *bind.WatchOpts, sink chan<- *NodeGroupRegistered, creator []common.Address, key [][32]byte) (event.Subscription, error) {
+	return watchEvents(&c.boundContract, opts, "NodeGroupRegistered", sink, c.ParseNodeGroupRegistered, toQuery(creator), toQuery(key))
+}
+
+// NameSpaceRegistered is the NameSpaceRegistered event of StorageManager.
+// NodeAddresses are the addresses as given by the creator, before sorting.
+type NameSpaceRegistered struct {
+	Creator       common.Address
+	Key           [32]byte
+	NodeAddresses []common.Address
+	Raw           types.Log
+}
+
+// ParseNameSpaceRegistered decodes a NameSpaceRegistered log.
+func (c *StorageManager) ParseNameSpaceRegistered(log types.Log) (*NameSpaceRegistered, error) {
+	ev := &NameSpaceRegistered{Raw: log}
+	if err := unpackLog(&c.boundContract, ev, "NameSpaceRegistered", log); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FilterNameSpaceRegistered returns the NameSpaceRegistered events in the block range of opts,
+// restricted to the given creators and keys when they are not empty.
+func (c *StorageManager) FilterNameSpaceRegistered(opts *bind.FilterOpts, creator []common.Address, key [][32]byte) ([]*NameSpaceRegistered, error) {
+	return filterEvents(&c.boundContract, opts, "NameSpaceRegistered", c.ParseNameSpaceRegistered, toQuery(creator), toQuery(key))
+}
+
+// WatchNameSpaceRegistered sends the new NameSpaceRegistered events to sink.
+func (c *StorageManager) WatchNameSpaceRegistered(opts *bind.WatchOpts, sink chan<- *NameSpaceRegistered, creator []common.Address, key [][32]byte) (event.Subscription, error) {
+	return watchEvents(&c.boundContract, opts, "NameSpaceRegistered", sink, c.ParseNameSpaceRegistered, toQuery(creator), toQuery(key))
+}
+
+// toQuery converts the accepted values of an indexed argument to a bind topic query.
+func toQuery[T any](values []T) []interface{} {
+	query := make([]interface{}, len(values))
+	for i, v := range values {
+		query[i] = v
+	}
+	return query
+}
@@ -0,0 +1,71 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var storageManagerAddress = common.HexToAddress("0x4000000000000000000000000000000000000004")
+
+func TestStorageManagerTransactions(t *testing.T) {
+	contract := NewStorageManager(storageManagerAddress, newFakeBackend())
+	opts := newTransactOpts(t)
+	addrs := []common.Address{{3}, {1}, {2}}
+
+	tx, err := contract.RegisterNodeGroup(opts, big.NewInt(2), addrs)
+	assert.NoError(t, err)
+	args := checkCalldata(t, StorageManagerABI, "registerNodeGroup(uint256,address[])", tx.Data())
+	assert.Equal(t, []interface{}{big.NewInt(2), addrs}, args)
+
+	tx, err = contract.RegisterNameSpace(opts, addrs)
+	assert.NoError(t, err)
+	args = checkCalldata(t, StorageManagerABI, "registerNameSpace(address[])", tx.Data())
+	assert.Equal(t, []interface{}{addrs}, args)
+}
+
+func TestStorageManagerCalls(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewStorageManager(storageManagerAddress, backend)
+	addrs := []common.Address{{1}, {2}}
+
+	backend.setOutput(t, StorageManagerABI, "NODEGROUP", NodeGroup{RequiredAmountOfSignatures: big.NewInt(1), Addrs: addrs})
+	group, err := contract.NodeGroup(nil, [32]byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, NodeGroup{RequiredAmountOfSignatures: big.NewInt(1), Addrs: addrs}, group)
+
+	backend.setOutput(t, StorageManagerABI, "NAMESPACE", NameSpace{Creator: common.Address{9}, Addr: addrs})
+	nameSpace, err := contract.NameSpace(nil, nameSpaceKey)
+	assert.NoError(t, err)
+	assert.Equal(t, NameSpace{Creator: common.Address{9}, Addr: addrs}, nameSpace)
+}
+
+func TestNameSpaceRegisteredEvent(t *testing.T) {
+	backend := newFakeBackend()
+	contract := NewStorageManager(storageManagerAddress, backend)
+	creator := common.Address{9}
+	addrs := []common.Address{{3}, {1}}
+
+	assert.Equal(t, "NameSpaceRegistered(address,bytes32,address[])", StorageManagerABI.Events["NameSpaceRegistered"].Sig)
+	log := eventLog(t, StorageManagerABI, "NameSpaceRegistered", storageManagerAddress,
+		[]common.Hash{common.BytesToHash(creator.Bytes()), nameSpaceKey}, addrs)
+	ev, err := contract.ParseNameSpaceRegistered(log)
+	assert.NoError(t, err)
+	assert.Equal(t, &NameSpaceRegistered{Creator: creator, Key: nameSpaceKey, NodeAddresses: addrs, Raw: log}, ev)
+
+	assert.Equal(t, "NodeGroupRegistered(address,bytes32,uint256,address[])", StorageManagerABI.Events["NodeGroupRegistered"].Sig)
+	groupLog := eventLog(t, StorageManagerABI, "NodeGroupRegistered", storageManagerAddress,
+		[]common.Hash{common.BytesToHash(creator.Bytes()), {7}}, big.NewInt(2), addrs)
+	group, err := contract.ParseNodeGroupRegistered(groupLog)
+	assert.NoError(t, err)
+	assert.Equal(t, &NodeGroupRegistered{Creator: creator, Key: [32]byte{7}, RequiredAmountOfSignatures: big.NewInt(2), NodeAddresses: addrs, Raw: groupLog}, group)
+
+	backend.logs = []types.Log{log, groupLog}
+	events, err := contract.FilterNameSpaceRegistered(&bind.FilterOpts{}, []common.Address{creator}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*NameSpaceRegistered{ev}, events)
+}
@@ -0,0 +1,130 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidG1Point is returned when a Pairing.G1Point is not a point of the BN254 G1 group.
+var ErrInvalidG1Point = errors.New("invalid G1 point")
+
+// G1Point is a Pairing.G1Point, the encoding of commitments and proofs on chain.
+// The point at infinity is (0, 0), as for the EVM precompiles.
+type G1Point struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// G1PointFromDigest converts a commitment, or the H of an opening proof, to a Pairing.G1Point.
+func G1PointFromDigest(d *kzg.Digest) G1Point {
+	return G1Point{
+		X: d.X.BigInt(new(big.Int)),
+		Y: d.Y.BigInt(new(big.Int)),
+	}
+}
+
+// ToDigest converts a Pairing.G1Point to a commitment. It returns ErrInvalidG1Point if a coordinate
+// is not reduced modulo the base field or if the point is not on the curve, G1 has no cofactor.
+func (p G1Point) ToDigest() (kzg.Digest, error) {
+	var d kzg.Digest
+	for _, c := range []*big.Int{p.X, p.Y} {
+		if c == nil || c.Sign() < 0 || c.Cmp(fp.Modulus()) >= 0 {
+			return d, fmt.Errorf("%w: coordinate out of range", ErrInvalidG1Point)
+		}
+	}
+	d.X.SetBigInt(p.X)
+	d.Y.SetBigInt(p.Y)
+	if !d.IsInfinity() && !d.IsOnCurve() {
+		return kzg.Digest{}, fmt.Errorf("%w: (%s, %s) is not on the curve", ErrInvalidG1Point, p.X, p.Y)
+	}
+	return d, nil
+}
+
+// ChallengeStatus mirrors the constants of src/libraries/ChallengeStatus.sol.
+type ChallengeStatus uint8
+
+const (
+	StatusChallengeCreated ChallengeStatus = iota
+	StatusFirstCommitSubmitted
+	StatusRecommitSubmitted
+	StatusCommitNotAgreed
+	StatusTemporaryAgreement
+	StatusAgreementReached
+	StatusChallengeSuccessful
+	StatusChallengeFailed
+)
+
+var challengeStatusNames = [...]string{
+	"CHALLENGE_CREATED",
+	"FIRST_COMMIT_SUBMITTED",
+	"RECOMMIT_SUBMITTED",
+	"COMMIT_NOT_AGREED",
+	"TEMPORARY_AGREEMENT",
+	"AGREEMENT_REACHED",
+	"CHALLENGE_SUCCESSFUL",
+	"CHALLENGE_FAILED",
+}
+
+func (s ChallengeStatus) String() string {
+	if int(s) < len(challengeStatusNames) {
+		return challengeStatusNames[s]
+	}
+	return fmt.Sprintf("ChallengeStatus(%d)", uint8(s))
+}
+
+// Done reports whether the challenge reached one of its final states.
+func (s ChallengeStatus) Done() bool {
+	return s == StatusChallengeSuccessful || s == StatusChallengeFailed
+}
+
+// Challenge is ChallengeContract.Challenge.
+type Challenge struct {
+	Nonce               *big.Int
+	Status              ChallengeStatus
+	Challenger          common.Address
+	StorageAddr         common.Address
+	NameSpaceKey        [32]byte
+	Start               *big.Int
+	End                 *big.Int
+	R                   *big.Int
+	AggregateCommitment G1Point
+	Point               *big.Int
+	TimeoutBlock        *big.Int
+}
+
+// ChallengeDetails is ChallengeContract.ChallengeDetatils, the state of the bisection.
+type ChallengeDetails struct {
+	ConsensusIndex          *big.Int
+	NoConsensusIndex        *big.Int
+	CurrentIndex            *big.Int
+	ConsensusCommitment     G1Point
+	NoConsensusCommitment   G1Point
+	CurrAggregateCommitment G1Point
+}
+
+// NodeInfo is the NodeInfo struct of src/NodeManager.sol.
+type NodeInfo struct {
+	Url             string
+	Name            string
+	StakedTokens    *big.Int
+	Location        string
+	MaxStorageSpace *big.Int
+	Addr            common.Address
+}
+
+// NodeGroup is the NodeGroup struct of src/StorageManager.sol, Addrs are sorted.
+type NodeGroup struct {
+	RequiredAmountOfSignatures *big.Int
+	Addrs                      []common.Address
+}
+
+// NameSpace is the NameSpace struct of src/StorageManager.sol, Addr is sorted.
+type NameSpace struct {
+	Creator common.Address
+	Addr    []common.Address
+}
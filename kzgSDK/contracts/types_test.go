@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1PointConversion(t *testing.T) {
+	var s fr.Element
+	s.SetRandom()
+	var d kzg.Digest
+	d.ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+
+	p := G1PointFromDigest(&d)
+	back, err := p.ToDigest()
+	assert.NoError(t, err)
+	assert.Equal(t, d, back)
+
+	// the generator of src/kzg/Constants.sol
+	var g kzg.Digest
+	g.ScalarMultiplicationBase(big.NewInt(1))
+	assert.Equal(t, G1Point{X: big.NewInt(1), Y: big.NewInt(2)}, G1PointFromDigest(&g))
+
+	// the point at infinity is (0, 0)
+	infinity := G1PointFromDigest(&kzg.Digest{})
+	assert.Zero(t, infinity.X.Sign())
+	assert.Zero(t, infinity.Y.Sign())
+	back, err = infinity.ToDigest()
+	assert.NoError(t, err)
+	assert.True(t, back.IsInfinity())
+
+	for _, invalid := range []G1Point{
+		{},
+		{X: big.NewInt(1), Y: big.NewInt(3)},
+		{X: big.NewInt(1), Y: new(big.Int).Add(fp.Modulus(), big.NewInt(2))},
+		{X: big.NewInt(-1), Y: big.NewInt(2)},
+	} {
+		_, err := invalid.ToDigest()
+		assert.ErrorIs(t, err, ErrInvalidG1Point)
+	}
+}
+
+func TestChallengeStatus(t *testing.T) {
+	assert.Equal(t, "CHALLENGE_CREATED", StatusChallengeCreated.String())
+	assert.Equal(t, "AGREEMENT_REACHED", ChallengeStatus(5).String())
+	assert.Equal(t, "CHALLENGE_FAILED", StatusChallengeFailed.String())
+	assert.Equal(t, "ChallengeStatus(8)", ChallengeStatus(8).String())
+	assert.True(t, StatusChallengeSuccessful.Done())
+	assert.True(t, StatusChallengeFailed.Done())
+	assert.False(t, StatusAgreementReached.Done())
+}
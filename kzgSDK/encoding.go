@@ -0,0 +1,113 @@
+package kzgsdk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const dChunkSize = 30
+
+// Blob encoding, version 1:
+//
+//	element 0:    version (1 byte) || data length (8 bytes, big endian) || zero padding
+//	element 1..n: the data split into 30-byte chunks, the last one right padded with zeros
+//
+// Every chunk is read as a 30-byte big endian integer, so it is always smaller than the
+// scalar field modulus and can be recovered byte for byte, including its leading zeros.
+const (
+	dEncodingVersion = 1
+	dHeaderLen       = 1 + 8
+)
+
+var (
+	// ErrInvalidEncoding is returned when a polynomial is not the encoding of a blob.
+	ErrInvalidEncoding = errors.New("invalid blob encoding")
+	// ErrUnsupportedVersion is returned when a polynomial uses an unknown encoding version.
+	ErrUnsupportedVersion = errors.New("unsupported blob encoding version")
+)
+
+func chunkBytes(data []byte, chunkSize int) [][]byte {
+	var chunks [][]byte
+	for i := 0; i < len(data); i += chunkSize {
+		end := i + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, data[i:end])
+	}
+	return chunks
+}
+
+// DataToPolynomial converts byte data into a slice of fr.Element representing a polynomial.
+// The first coefficient is a header holding the encoding version and the data length, see PolynomialToData.
+func DataToPolynomial(data []byte) []fr.Element {
+	chunks := chunkBytes(data, dChunkSize)
+	ps := make([]fr.Element, len(chunks)+1)
+
+	var header [dChunkSize]byte
+	header[0] = dEncodingVersion
+	binary.BigEndian.PutUint64(header[1:dHeaderLen], uint64(len(data)))
+	ps[0].SetBytes(header[:])
+
+	for i, chunk := range chunks {
+		var padded [dChunkSize]byte
+		copy(padded[:], chunk)
+		ps[i+1].SetBytes(padded[:])
+	}
+	return ps
+}
+
+// PolynomialToData recovers the data encoded by DataToPolynomial.
+func PolynomialToData(polynomial []fr.Element) ([]byte, error) {
+	if len(polynomial) == 0 {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidEncoding)
+	}
+	header, err := elementToChunk(&polynomial[0])
+	if err != nil {
+		return nil, err
+	}
+	if header[0] != dEncodingVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header[0])
+	}
+	for _, b := range header[dHeaderLen:] {
+		if b != 0 {
+			return nil, fmt.Errorf("%w: non-zero header padding", ErrInvalidEncoding)
+		}
+	}
+	length := binary.BigEndian.Uint64(header[1:dHeaderLen])
+	chunks := uint64(len(polynomial) - 1)
+	if length > chunks*dChunkSize || length+dChunkSize <= chunks*dChunkSize {
+		return nil, fmt.Errorf("%w: length %d does not match %d chunks", ErrInvalidEncoding, length, chunks)
+	}
+
+	data := make([]byte, 0, chunks*dChunkSize)
+	for i := range polynomial[1:] {
+		chunk, err := elementToChunk(&polynomial[i+1])
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:]...)
+	}
+	for _, b := range data[length:] {
+		if b != 0 {
+			return nil, fmt.Errorf("%w: non-zero padding", ErrInvalidEncoding)
+		}
+	}
+	return data[:length], nil
+}
+
+// elementToChunk returns the 30-byte big endian chunk held by e.
+func elementToChunk(e *fr.Element) ([dChunkSize]byte, error) {
+	var chunk [dChunkSize]byte
+	b := e.Bytes()
+	for _, v := range b[:fr.Bytes-dChunkSize] {
+		if v != 0 {
+			return chunk, fmt.Errorf("%w: element does not fit in %d bytes", ErrInvalidEncoding, dChunkSize)
+		}
+	}
+	copy(chunk[:], b[fr.Bytes-dChunkSize:])
+	return chunk, nil
+}
@@ -0,0 +1,92 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataToPolynomialRoundTrip(t *testing.T) {
+	random := make([]byte, 1000)
+	_, err := rand.Read(random)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"nil", nil},
+		{"one byte", []byte{0x42}},
+		{"zero byte", []byte{0}},
+		{"leading zeros", append(make([]byte, 29), 1)},
+		{"trailing zeros", append([]byte{1, 2, 3}, make([]byte, 40)...)},
+		{"only zeros", make([]byte, 2*dChunkSize)},
+		{"full chunk", bytes.Repeat([]byte{0xff}, dChunkSize)},
+		{"chunk and one byte", bytes.Repeat([]byte{0xff}, dChunkSize+1)},
+		{"random", random},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poly := DataToPolynomial(tt.data)
+			assert.Equal(t, 1+(len(tt.data)+dChunkSize-1)/dChunkSize, len(poly))
+			data, err := PolynomialToData(poly)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.data), len(data))
+			assert.True(t, bytes.Equal(tt.data, data))
+		})
+	}
+}
+
+func TestDataToPolynomialCommitment(t *testing.T) {
+	// data differing only by trailing zeros has different commitments
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	c0, err := kzg.Commit(DataToPolynomial([]byte{1, 2, 3}), srs.Pk)
+	assert.NoError(t, err)
+	c1, err := kzg.Commit(DataToPolynomial([]byte{1, 2, 3, 0}), srs.Pk)
+	assert.NoError(t, err)
+	assert.False(t, c0.Equal(&c1))
+}
+
+func TestPolynomialToDataErrors(t *testing.T) {
+	_, err := PolynomialToData(nil)
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+	poly := DataToPolynomial([]byte("The sampling party generates n+1 distinct points"))
+
+	// unknown version
+	bad := append([]fr.Element{}, poly...)
+	header := bad[0].Bytes()
+	header[fr.Bytes-dChunkSize] = dEncodingVersion + 1
+	bad[0].SetBytes(header[:])
+	_, err = PolynomialToData(bad)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	// missing chunk
+	_, err = PolynomialToData(poly[:len(poly)-1])
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+	// extra chunk
+	_, err = PolynomialToData(append(append([]fr.Element{}, poly...), fr.Element{}))
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+	// element larger than a chunk
+	bad = append([]fr.Element{}, poly...)
+	bad[1].SetOne()
+	bad[1].Neg(&bad[1])
+	_, err = PolynomialToData(bad)
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+	// non-zero padding after the data
+	bad = append([]fr.Element{}, poly...)
+	last := bad[len(bad)-1].Bytes()
+	last[fr.Bytes-1] = 1
+	bad[len(bad)-1].SetBytes(last[:])
+	_, err = PolynomialToData(bad)
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+}
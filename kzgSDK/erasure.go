@@ -0,0 +1,202 @@
+package kzgsdk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+)
+
+var (
+	// ErrNotEnoughCells is returned when less than half of the cells of an extended blob are available.
+	ErrNotEnoughCells = errors.New("not enough cells to reconstruct the blob")
+	// ErrInconsistentCells is returned when the available cells are not evaluations of a single blob.
+	ErrInconsistentCells = errors.New("cells are not consistent with a blob")
+)
+
+// Extension extends blobs of up to n coefficients into 2n cells, the evaluations of the blob
+// polynomial f over the 2n-th roots of unity: cell i = f(ωⁱ).
+// f is unchanged, so the extended blob has the commitment of the original one (the Lagrange
+// commitment of the cells over the 2n domain is kzg.Commit(f)), and any n cells are enough to recover f.
+type Extension struct {
+	n      uint64
+	domain *fft.Domain
+}
+
+// NewExtension creates the extension of blobs of up to n coefficients, n must be a power of two.
+func NewExtension(n uint64) (*Extension, error) {
+	if n < 1 || n != ecc.NextPowerOfTwo(n) {
+		return nil, fmt.Errorf("%w: %d", ErrDomainSize, n)
+	}
+	return &Extension{n: n, domain: fft.NewDomain(2 * n)}, nil
+}
+
+// Extend evaluates polynomial over the 2n domain and returns the 2n cells of the extended blob.
+func (e *Extension) Extend(polynomial []fr.Element) ([]fr.Element, error) {
+	if len(polynomial) == 0 || uint64(len(polynomial)) > e.n {
+		return nil, fmt.Errorf("%w: %d coefficients, extension of %d", ErrDomainSize, len(polynomial), e.n)
+	}
+	cells := make([]fr.Element, 2*e.n)
+	copy(cells, polynomial)
+	e.domain.FFT(cells, fft.DIF)
+	fft.BitReverse(cells)
+	return cells, nil
+}
+
+// Reconstruct recovers the n coefficients of a blob from the cells of its extension. available[i]
+// tells whether cells[i] is known, the value of a missing cell is ignored. At least n cells are needed.
+//
+// With Z(x) = ∏(x - ωⁱ) over the missing cells, (f·Z)(ωⁱ) is cells[i]·Z(ωⁱ) on available cells and
+// 0 on missing ones, f·Z is interpolated from these evaluations and divided by Z on a coset of the
+// domain, where Z does not vanish. Z is built with a subproduct tree, so the whole reconstruction
+// takes O(n·log²n).
+func (e *Extension) Reconstruct(cells []fr.Element, available []bool) ([]fr.Element, error) {
+	size := 2 * e.n
+	if uint64(len(cells)) != size || uint64(len(available)) != size {
+		return nil, fmt.Errorf("%w: %d cells, extension of %d", ErrDomainSize, len(cells), size)
+	}
+	missing := uint64(0)
+	for _, ok := range available {
+		if !ok {
+			missing++
+		}
+	}
+	if missing > e.n {
+		return nil, fmt.Errorf("%w: %d of %d cells", ErrNotEnoughCells, size-missing, size)
+	}
+
+	// coefficients of Z, the vanishing polynomial of the missing cells
+	roots := make([]fr.Element, 0, missing)
+	var root fr.Element
+	root.SetOne()
+	for _, ok := range available {
+		if !ok {
+			roots = append(roots, root)
+		}
+		root.Mul(&root, &e.domain.Generator)
+	}
+	zero := make([]fr.Element, size)
+	copy(zero, vanishingPolynomial(roots))
+
+	// (f·Z)(ωⁱ)
+	zeroEvals := append([]fr.Element{}, zero...)
+	e.domain.FFT(zeroEvals, fft.DIF)
+	fft.BitReverse(zeroEvals)
+	product := make([]fr.Element, size)
+	for i := range product {
+		if available[i] {
+			product[i].Mul(&cells[i], &zeroEvals[i])
+		}
+	}
+	e.domain.FFTInverse(product, fft.DIF)
+	fft.BitReverse(product)
+
+	// f = (f·Z) / Z on the coset
+	e.domain.FFT(product, fft.DIF, fft.OnCoset())
+	e.domain.FFT(zero, fft.DIF, fft.OnCoset())
+	zero = fr.BatchInvert(zero)
+	for i := range product {
+		product[i].Mul(&product[i], &zero[i])
+	}
+	e.domain.FFTInverse(product, fft.DIT, fft.OnCoset())
+
+	for i := e.n; i < size; i++ {
+		if !product[i].IsZero() {
+			return nil, ErrInconsistentCells
+		}
+	}
+	return product[:e.n], nil
+}
+
+// fftMultiplyThreshold is the size of the products from which multiplyPolynomials uses FFTs.
+const fftMultiplyThreshold = 64
+
+// vanishingPolynomial returns the coefficients of ∏(x - r) over roots. The factors are multiplied
+// pairwise, then their products pairwise and so on up to the root of the subproduct tree: with
+// FFT multiplications, this takes O(m·log²m) for m roots rather than O(m²) one factor at a time.
+func vanishingPolynomial(roots []fr.Element) []fr.Element {
+	if len(roots) == 0 {
+		return []fr.Element{fr.One()}
+	}
+	level := make([][]fr.Element, len(roots))
+	for i := range roots {
+		level[i] = make([]fr.Element, 2)
+		level[i][0].Neg(&roots[i])
+		level[i][1].SetOne()
+	}
+	domains := make(map[uint64]*fft.Domain)
+	for len(level) > 1 {
+		next := make([][]fr.Element, 0, (len(level)+1)/2)
+		for i := 0; i+1 < len(level); i += 2 {
+			next = append(next, multiplyPolynomials(level[i], level[i+1], domains))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// multiplyPolynomials returns a·b, multiplying their evaluations over a domain of domains, which
+// caches the domains by size, once the product has fftMultiplyThreshold coefficients.
+func multiplyPolynomials(a []fr.Element, b []fr.Element, domains map[uint64]*fft.Domain) []fr.Element {
+	size := len(a) + len(b) - 1
+	if size < fftMultiplyThreshold {
+		product := make([]fr.Element, size)
+		var t fr.Element
+		for i := range a {
+			for j := range b {
+				t.Mul(&a[i], &b[j])
+				product[i+j].Add(&product[i+j], &t)
+			}
+		}
+		return product
+	}
+	n := ecc.NextPowerOfTwo(uint64(size))
+	domain, ok := domains[n]
+	if !ok {
+		domain = fft.NewDomain(n)
+		domains[n] = domain
+	}
+	evalsA := make([]fr.Element, n)
+	copy(evalsA, a)
+	evalsB := make([]fr.Element, n)
+	copy(evalsB, b)
+	domain.FFT(evalsA, fft.DIF)
+	domain.FFT(evalsB, fft.DIF)
+	for i := range evalsA {
+		evalsA[i].Mul(&evalsA[i], &evalsB[i])
+	}
+	domain.FFTInverse(evalsA, fft.DIT)
+	return evalsA[:size]
+}
+
+// ReconstructData recovers the data of a blob encoded with DataToPolynomial from the cells of its extension.
+func (e *Extension) ReconstructData(cells []fr.Element, available []bool) ([]byte, error) {
+	polynomial, err := e.Reconstruct(cells, available)
+	if err != nil {
+		return nil, err
+	}
+	header, err := elementToChunk(&polynomial[0])
+	if err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint64(header[1:dHeaderLen])
+	chunks := length / dChunkSize
+	if length%dChunkSize != 0 {
+		chunks++
+	}
+	if chunks >= e.n {
+		return nil, fmt.Errorf("%w: length %d does not fit in %d coefficients", ErrInvalidEncoding, length, e.n)
+	}
+	for _, c := range polynomial[1+chunks:] {
+		if !c.IsZero() {
+			return nil, fmt.Errorf("%w: non-zero coefficient after the data", ErrInvalidEncoding)
+		}
+	}
+	return PolynomialToData(polynomial[:1+chunks])
+}
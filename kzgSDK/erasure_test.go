@@ -0,0 +1,147 @@
+package kzgsdk
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensionCommitment(t *testing.T) {
+	srs, err := kzg.NewSRS(256, big.NewInt(42))
+	assert.NoError(t, err)
+	extension, err := NewExtension(128)
+	assert.NoError(t, err)
+	lagrange, err := NewLagrangeSRS(srs, 256)
+	assert.NoError(t, err)
+
+	polynomial := randomPolynomial(100)
+	cells, err := extension.Extend(polynomial)
+	assert.NoError(t, err)
+	assert.Equal(t, 256, len(cells))
+
+	commit, err := kzg.Commit(polynomial, srs.Pk)
+	assert.NoError(t, err)
+	extendedCommit, err := lagrange.Commit(cells)
+	assert.NoError(t, err)
+	assert.Equal(t, commit, extendedCommit)
+
+	// a cell of the extension is an opening of the original commitment
+	proof, err := lagrange.Open(cells, 201)
+	assert.NoError(t, err)
+	assert.Equal(t, cells[201], proof.ClaimedValue)
+	assert.NoError(t, lagrange.Verify(&commit, &proof, 201, srs.Vk))
+}
+
+func TestReconstruct(t *testing.T) {
+	const n = 64
+	extension, err := NewExtension(n)
+	assert.NoError(t, err)
+	polynomial := randomPolynomial(n)
+	cells, err := extension.Extend(polynomial)
+	assert.NoError(t, err)
+
+	rng := mrand.New(mrand.NewSource(1))
+	patterns := map[string][]bool{
+		"all":         available(2*n, nil),
+		"first half":  available(2*n, func(i int) bool { return i < n }),
+		"second half": available(2*n, func(i int) bool { return i >= n }),
+		"even cells":  available(2*n, func(i int) bool { return i%2 == 0 }),
+		"random half": available(2*n, nil),
+	}
+	for _, i := range rng.Perm(2 * n)[:n] {
+		patterns["random half"][i] = false
+	}
+	for name, pattern := range patterns {
+		t.Run(name, func(t *testing.T) {
+			damaged := append([]fr.Element{}, cells...)
+			for i, ok := range pattern {
+				if !ok {
+					damaged[i].SetRandom()
+				}
+			}
+			recovered, err := extension.Reconstruct(damaged, pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, polynomial, recovered)
+		})
+	}
+
+	// one cell short
+	pattern := available(2*n, func(i int) bool { return i < n })
+	pattern[0] = false
+	_, err = extension.Reconstruct(cells, pattern)
+	assert.ErrorIs(t, err, ErrNotEnoughCells)
+
+	// a corrupted cell is detected when more than n cells are available
+	corrupted := append([]fr.Element{}, cells...)
+	corrupted[3].SetRandom()
+	_, err = extension.Reconstruct(corrupted, available(2*n, nil))
+	assert.ErrorIs(t, err, ErrInconsistentCells)
+}
+
+func TestVanishingPolynomial(t *testing.T) {
+	for _, m := range []int{0, 1, 2, 7, 63, 64, 65, 300} {
+		roots := randomPolynomial(m)
+		// one factor at a time
+		expected := []fr.Element{fr.One()}
+		for _, root := range roots {
+			next := make([]fr.Element, len(expected)+1)
+			for j := range expected {
+				var t fr.Element
+				t.Mul(&expected[j], &root)
+				next[j].Sub(&next[j], &t)
+				next[j+1].Add(&next[j+1], &expected[j])
+			}
+			expected = next
+		}
+		assert.Equal(t, expected, vanishingPolynomial(roots), "%d roots", m)
+	}
+
+	// a reconstruction whose vanishing polynomial is built with FFTs
+	const n = 512
+	extension, err := NewExtension(n)
+	assert.NoError(t, err)
+	polynomial := randomPolynomial(n)
+	cells, err := extension.Extend(polynomial)
+	assert.NoError(t, err)
+	pattern := available(2*n, nil)
+	for _, i := range mrand.New(mrand.NewSource(2)).Perm(2 * n)[:n] {
+		pattern[i] = false
+	}
+	recovered, err := extension.Reconstruct(cells, pattern)
+	assert.NoError(t, err)
+	assert.Equal(t, polynomial, recovered)
+}
+
+func TestReconstructData(t *testing.T) {
+	extension, err := NewExtension(8)
+	assert.NoError(t, err)
+	for _, data := range [][]byte{
+		{},
+		[]byte("The sampling party verifies the correctness"),
+		append([]byte("trailing zeros"), make([]byte, 100)...),
+	} {
+		cells, err := extension.Extend(DataToPolynomial(data))
+		assert.NoError(t, err)
+		pattern := available(16, func(i int) bool { return i%2 == 1 })
+		recovered, err := extension.ReconstructData(cells, pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, len(data), len(recovered))
+		assert.Equal(t, string(data), string(recovered))
+	}
+
+	_, err = extension.Extend(DataToPolynomial(make([]byte, 9*dChunkSize)))
+	assert.ErrorIs(t, err, ErrDomainSize)
+}
+
+// available returns the availability of size cells, all available when keep is nil.
+func available(size int, keep func(i int) bool) []bool {
+	pattern := make([]bool, size)
+	for i := range pattern {
+		pattern[i] = keep == nil || keep(i)
+	}
+	return pattern
+}
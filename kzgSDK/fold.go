@@ -0,0 +1,120 @@
+package kzgsdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// PolynomialIterator reads blobs one at a time with their absolute namespace indices, as the
+// blobstore iterator does. Next returns false at the end of the blobs or on an error, see Err.
+type PolynomialIterator interface {
+	Next(ctx context.Context) bool
+	Index() uint64
+	Polynomial() []fr.Element
+	Err() error
+}
+
+// Folder computes ∑ r_i·f_i, the polynomial committed by an aggregate of AggregateRange, from
+// blobs added one at a time. Only the folded polynomial is kept, so folding a range takes the
+// memory of its largest blob rather than of the whole range.
+type Folder struct {
+	srs    *kzg.SRS
+	seed   FoldSeed
+	folded []fr.Element
+	count  int
+}
+
+// NewFolder returns an empty Folder using seed.
+func (sdk *DomiconSdk) NewFolder(seed FoldSeed) *Folder {
+	return &Folder{srs: sdk.srs, seed: seed}
+}
+
+// Add folds the blob at namespace index into the polynomial. It returns ErrPolynomialTooLarge
+// if the blob does not fit in the srs, leaving the polynomial unchanged.
+func (f *Folder) Add(index uint64, polynomial []fr.Element) error {
+	if len(polynomial) > len(f.srs.Pk.G1) {
+		return fmt.Errorf("%w: %d coefficients at index %d, srs size %d", ErrPolynomialTooLarge, len(polynomial), index, len(f.srs.Pk.G1))
+	}
+	if len(polynomial) > len(f.folded) {
+		f.folded = append(f.folded, make([]fr.Element, len(polynomial)-len(f.folded))...)
+	}
+	coefficient := f.seed.Coefficient(index)
+	var pj fr.Element
+	for j := range polynomial {
+		pj.Mul(&polynomial[j], &coefficient)
+		f.folded[j].Add(&f.folded[j], &pj)
+	}
+	f.count++
+	return nil
+}
+
+// AddData folds the data of the blob at namespace index, encoded with DataToPolynomial.
+func (f *Folder) AddData(index uint64, data []byte) error {
+	return f.Add(index, DataToPolynomial(data))
+}
+
+// AddAll folds the blobs of it, which must be those of the namespace indices start, ..., end in
+// that order. It returns ErrInvalidRange if it skips, repeats or misses an index, and otherwise
+// the error that stopped it, if any.
+func (f *Folder) AddAll(ctx context.Context, it PolynomialIterator, start uint64, end uint64) error {
+	if start > end {
+		return fmt.Errorf("%w: [%d, %d]", ErrInvalidRange, start, end)
+	}
+	next, done := start, false
+	for it.Next(ctx) {
+		if done || it.Index() != next {
+			return fmt.Errorf("%w: blob %d where %d of [%d, %d] was expected", ErrInvalidRange, it.Index(), next, start, end)
+		}
+		if err := f.Add(it.Index(), it.Polynomial()); err != nil {
+			return err
+		}
+		next, done = next+1, next == end
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if !done {
+		return fmt.Errorf("%w: no blob %d of [%d, %d]", ErrInvalidRange, next, start, end)
+	}
+	return nil
+}
+
+// Len returns the number of blobs folded so far.
+func (f *Folder) Len() int {
+	return f.count
+}
+
+// Polynomial returns the folded polynomial. It is the buffer of the Folder, which further
+// calls to Add modify.
+func (f *Folder) Polynomial() []fr.Element {
+	return f.folded
+}
+
+// Open computes the opening proof at openPoint of the folded polynomial. It returns
+// ErrEmptyInput if nothing was folded.
+func (f *Folder) Open(openPoint fr.Element) (kzg.OpeningProof, error) {
+	if len(f.folded) == 0 {
+		return kzg.OpeningProof{}, ErrEmptyInput
+	}
+	return kzg.Open(f.folded, openPoint, f.srs.Pk)
+}
+
+// OpenIterator computes the opening proof of OpenRange over [start, end] from the blobs of it,
+// reading them one at a time. The iterator must yield the blobs of the range in order, see AddAll.
+func (sdk *DomiconSdk) OpenIterator(
+	ctx context.Context,
+	it PolynomialIterator,
+	openPoint fr.Element,
+	seed FoldSeed,
+	start uint64,
+	end uint64,
+) (kzg.OpeningProof, error) {
+	folder := sdk.NewFolder(seed)
+	if err := folder.AddAll(ctx, it, start, end); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return folder.Open(openPoint)
+}
@@ -0,0 +1,117 @@
+package kzgsdk
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceIterator yields polynomials[i] for each i of indices, failing with err after the last one.
+type sliceIterator struct {
+	polynomials [][]fr.Element
+	indices     []uint64
+	next        int
+	err         error
+}
+
+// rangeIterator iterates over polynomials[start:end+1].
+func rangeIterator(polynomials [][]fr.Element, start uint64, end uint64) *sliceIterator {
+	it := &sliceIterator{polynomials: polynomials}
+	for i := start; i <= end; i++ {
+		it.indices = append(it.indices, i)
+	}
+	return it
+}
+
+func (it *sliceIterator) Next(context.Context) bool {
+	if it.next >= len(it.indices) {
+		return false
+	}
+	it.next++
+	return true
+}
+
+func (it *sliceIterator) Index() uint64 {
+	return it.indices[it.next-1]
+}
+
+func (it *sliceIterator) Polynomial() []fr.Element {
+	return it.polynomials[it.Index()]
+}
+
+func (it *sliceIterator) Err() error {
+	return it.err
+}
+
+func TestFolder(t *testing.T) {
+	srs, err := kzg.NewSRS(32, big.NewInt(42))
+	require.NoError(t, err)
+	sdk := NewDomiconSdk(srs)
+	const n = 10
+	polys := make([][]fr.Element, n)
+	commits := make([]kzg.Digest, n)
+	for i := range polys {
+		// sizes going up and down, so the buffer grows in the middle of the range
+		polys[i] = randomPolynomial(2 + (i*7)%11)
+		commits[i], err = sdk.Commit(polys[i])
+		require.NoError(t, err)
+	}
+	seed := randomSeed()
+	var point fr.Element
+	point.SetRandom()
+
+	for _, r := range [][2]uint64{{0, 0}, {4, 4}, {0, n - 1}, {3, 8}} {
+		start, end := r[0], r[1]
+		expected, err := AggregatePolynomials(polys, seed, start, end)
+		require.NoError(t, err)
+		folder := sdk.NewFolder(seed)
+		for i := start; i <= end; i++ {
+			require.NoError(t, folder.Add(i, polys[i]))
+		}
+		assert.Equal(t, expected, folder.Polynomial())
+		assert.Equal(t, int(end-start+1), folder.Len())
+
+		aggregate, err := AggregateRange(commits, seed, start, end)
+		require.NoError(t, err)
+		proof, err := sdk.OpenIterator(context.Background(), rangeIterator(polys, start, end), point, seed, start, end)
+		require.NoError(t, err)
+		assert.NoError(t, sdk.Verify(&aggregate, &proof, point))
+		rangeProof, err := sdk.OpenRange(polys, point, seed, start, end)
+		require.NoError(t, err)
+		assert.Equal(t, rangeProof, proof)
+	}
+
+	data := []byte("folded data")
+	folder := sdk.NewFolder(seed)
+	require.NoError(t, folder.AddData(2, data))
+	expected, err := AggregatePolynomials([][]fr.Element{nil, nil, DataToPolynomial(data)}, seed, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, expected, folder.Polynomial())
+
+	folder = sdk.NewFolder(seed)
+	_, err = folder.Open(point)
+	assert.ErrorIs(t, err, ErrEmptyInput)
+	assert.ErrorIs(t, folder.Add(0, randomPolynomial(33)), ErrPolynomialTooLarge)
+	assert.Equal(t, 0, folder.Len())
+
+	failure := errors.New("read failure")
+	failing := rangeIterator(polys, 0, 2)
+	failing.err = failure
+	_, err = sdk.OpenIterator(context.Background(), failing, point, seed, 0, 2)
+	assert.ErrorIs(t, err, failure)
+
+	// an iterator yielding other blobs than those of the range
+	for _, indices := range [][]uint64{{2, 4}, {2, 3, 3, 4}, {2, 3}, {2, 3, 4, 5}, {3, 2, 4}, {}} {
+		it := &sliceIterator{polynomials: polys, indices: indices}
+		_, err = sdk.OpenIterator(context.Background(), it, point, seed, 2, 4)
+		assert.ErrorIs(t, err, ErrInvalidRange, "indices %v", indices)
+	}
+	_, err = sdk.OpenIterator(context.Background(), rangeIterator(polys, 0, 2), point, seed, 2, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,460 @@
+// Package indexer keeps a local index of the namespace commitments of a CommitmentManager, built
+// from its SendDACommitment events.
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// DefaultBatchSize is the number of blocks whose logs are requested at once.
+	DefaultBatchSize = 1000
+	// DefaultReorgDepth is the number of blocks whose hashes are kept to handle reorgs.
+	DefaultReorgDepth = 128
+	// DefaultPollInterval is the interval at which Run looks for new blocks.
+	DefaultPollInterval = 12 * time.Second
+)
+
+var (
+	// ErrNotIndexed is returned for namespace indices beyond the commitments indexed so far.
+	ErrNotIndexed = errors.New("commitment not indexed")
+	// ErrReorgTooDeep is returned when the chain reorganized below the blocks the indexer keeps.
+	ErrReorgTooDeep = errors.New("reorg deeper than the indexer keeps")
+)
+
+// errForked is returned by indexRange when the parent of the range is not the last indexed block.
+var errForked = errors.New("chain forked below the indexed range")
+
+// sendDACommitmentID is the topic of SendDACommitment logs.
+var sendDACommitmentID = contracts.CommitmentManagerABI.Events["SendDACommitment"].ID
+
+// Chain is the part of an ethclient.Client the indexer reads.
+type Chain interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// Config configures an Indexer.
+type Config struct {
+	// Address is the address of the CommitmentManager.
+	Address common.Address
+	// StartBlock is the first block to index, the block the CommitmentManager was deployed in.
+	StartBlock uint64
+	// Confirmations is the number of blocks a block must be buried under to be indexed.
+	Confirmations uint64
+	// BatchSize is the number of blocks indexed at once, DefaultBatchSize if zero.
+	BatchSize uint64
+	// ReorgDepth is the depth of the deepest reorg handled, DefaultReorgDepth if zero.
+	ReorgDepth uint64
+	// PollInterval is the interval at which Run syncs, DefaultPollInterval if zero.
+	PollInterval time.Duration
+}
+
+// Indexer indexes the commitments submitted to a namespace: the commitment of the i-th
+// SendDACommitment event of a namespace is at index i, as in CommitmentManager.nameSpaceCommitments,
+// the events being ordered by block, then by position in the block.
+//
+// On a reorg, the commitments of the blocks that left the chain are removed, and the new blocks
+// are indexed. Everything is kept in an ethdb.KeyValueStore, so that a restarted Indexer goes on
+// from the last indexed block.
+type Indexer struct {
+	db     ethdb.KeyValueStore
+	chain  Chain
+	config Config
+
+	// mu serializes the writes and keeps the reads consistent with them.
+	mu sync.RWMutex
+}
+
+// New returns an Indexer storing its index in db.
+func New(db ethdb.KeyValueStore, chain Chain, config Config) *Indexer {
+	if config.BatchSize == 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.ReorgDepth == 0 {
+		config.ReorgDepth = DefaultReorgDepth
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	return &Indexer{db: db, chain: chain, config: config}
+}
+
+// NextBlock returns the next block to index.
+func (ix *Indexer) NextBlock() (uint64, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.nextBlock()
+}
+
+func (ix *Indexer) nextBlock() (uint64, error) {
+	next, ok, err := readUint64(ix.db, nextKey)
+	if err != nil || !ok {
+		return ix.config.StartBlock, err
+	}
+	return next, nil
+}
+
+// NameSpaceIndex returns the number of commitments indexed for the namespace, which is
+// CommitmentManager.nameSpaceIndex as of the last indexed block.
+func (ix *Indexer) NameSpaceIndex(nameSpaceKey [32]byte) (uint64, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	count, _, err := readUint64(ix.db, countKey(nameSpaceKey))
+	return count, err
+}
+
+// CommitmentsForNamespace returns the commitments of the namespace at the indices of the
+// inclusive range [from, to], the first element being the one at from. It returns ErrNotIndexed
+// if to is not below NameSpaceIndex.
+func (ix *Indexer) CommitmentsForNamespace(nameSpaceKey [32]byte, from uint64, to uint64) ([]kzg.Digest, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if from > to {
+		return nil, fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+	count, _, err := readUint64(ix.db, countKey(nameSpaceKey))
+	if err != nil {
+		return nil, err
+	}
+	if to >= count {
+		return nil, fmt.Errorf("%w: index %d of namespace %x, %d indexed", ErrNotIndexed, to, nameSpaceKey, count)
+	}
+	commits := make([]kzg.Digest, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		value, err := ix.db.Get(commitKey(nameSpaceKey, i))
+		if err != nil {
+			return nil, err
+		}
+		commit, err := decodeCommitment(value)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// Run syncs every PollInterval until ctx is done. Sync errors are logged and retried.
+func (ix *Indexer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(ix.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := ix.Sync(ctx); err != nil {
+			if errors.Is(err, ErrReorgTooDeep) {
+				return err
+			}
+			log.Warn("Failed to sync the commitment index", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync indexes the blocks up to the head of the chain minus Confirmations, first rolling back the
+// blocks that left the chain, and again whenever a batch does not extend the indexed blocks. It returns ErrReorgTooDeep if none of the last ReorgDepth blocks
+// indexed is still in the chain.
+func (ix *Indexer) Sync(ctx context.Context) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	head, err := ix.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if head.Number.Uint64() < ix.config.Confirmations {
+		return nil
+	}
+	target := head.Number.Uint64() - ix.config.Confirmations
+	if err := ix.handleReorg(ctx); err != nil {
+		return err
+	}
+	next, err := ix.nextBlock()
+	if err != nil {
+		return err
+	}
+	for next <= target {
+		end := min(next+ix.config.BatchSize-1, target)
+		err := ix.indexRange(ctx, next, end)
+		if errors.Is(err, errForked) {
+			// the chain reorganized since the last batch, its blocks are rolled back before going on
+			if err := ix.handleReorg(ctx); err != nil {
+				return err
+			}
+			rolledBack, nextErr := ix.nextBlock()
+			if nextErr != nil {
+				return nextErr
+			}
+			if rolledBack == next {
+				return err
+			}
+			next = rolledBack
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		next = end + 1
+	}
+	return nil
+}
+
+// handleReorg rolls back the blocks that are no longer in the chain.
+func (ix *Indexer) handleReorg(ctx context.Context) error {
+	next, err := ix.nextBlock()
+	if err != nil || next == ix.config.StartBlock {
+		return err
+	}
+	// the hash of the block below the last ReorgDepth blocks is kept as the common ancestor of
+	// the deepest reorg handled
+	low := ix.config.StartBlock
+	if next > ix.config.ReorgDepth+1 && next-ix.config.ReorgDepth-1 > low {
+		low = next - ix.config.ReorgDepth - 1
+	}
+	blocks, err := blocksFrom(ix.db, hashPrefix, low)
+	if err != nil {
+		return err
+	}
+	// the last block indexed is always recorded, most often it is still in the chain
+	for i := len(blocks) - 1; i >= 0; i-- {
+		stored, _, err := readHash(ix.db, blocks[i])
+		if err != nil {
+			return err
+		}
+		header, err := ix.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(blocks[i]))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && header.Hash() == stored {
+			if i == len(blocks)-1 {
+				return nil
+			}
+			return ix.rollback(blocks[i] + 1)
+		}
+	}
+	if low > ix.config.StartBlock {
+		return fmt.Errorf("%w: no block after %d is in the chain", ErrReorgTooDeep, low)
+	}
+	return ix.rollback(ix.config.StartBlock)
+}
+
+// rollback removes the commitments of the blocks from from on, and makes from the next block to index.
+func (ix *Indexer) rollback(from uint64) error {
+	undone, err := blocksFrom(ix.db, undoPrefix, from)
+	if err != nil {
+		return err
+	}
+	hashes, err := blocksFrom(ix.db, hashPrefix, from)
+	if err != nil {
+		return err
+	}
+	batch := ix.db.NewBatch()
+	counts := make(map[[32]byte]uint64)
+	for i := len(undone) - 1; i >= 0; i-- {
+		keys, err := ix.db.Get(blockKey(undoPrefix, undone[i]))
+		if err != nil {
+			return err
+		}
+		for j := len(keys) - 32; j >= 0; j -= 32 {
+			var nameSpaceKey [32]byte
+			copy(nameSpaceKey[:], keys[j:j+32])
+			count, err := ix.count(counts, nameSpaceKey)
+			if err != nil {
+				return err
+			}
+			counts[nameSpaceKey] = count - 1
+			if err := batch.Delete(commitKey(nameSpaceKey, count-1)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(blockKey(undoPrefix, undone[i])); err != nil {
+			return err
+		}
+	}
+	for _, block := range hashes {
+		if err := batch.Delete(blockKey(hashPrefix, block)); err != nil {
+			return err
+		}
+	}
+	if err := writeCounts(batch, counts); err != nil {
+		return err
+	}
+	if err := batch.Put(nextKey, encodeUint64(from)); err != nil {
+		return err
+	}
+	log.Info("Rolled back the commitment index", "from", from, "blocks", len(hashes))
+	return batch.Write()
+}
+
+// indexRange indexes the blocks [from, to] in a single write.
+func (ix *Indexer) indexRange(ctx context.Context, from uint64, to uint64) error {
+	last, err := ix.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return err
+	}
+	// handleReorg looks for the common ancestor among the last ReorgDepth blocks and their
+	// parent, so the hash of each of them is kept. They are read by following the parent
+	// hashes, which makes them the blocks of a single chain.
+	hashes := map[uint64]common.Hash{to: last.Hash()}
+	low := max(from, to-min(to, ix.config.ReorgDepth))
+	first := last
+	for block := to; block > low; block-- {
+		header, err := ix.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(block-1))
+		if err != nil {
+			return err
+		}
+		if header.Hash() != first.ParentHash {
+			return fmt.Errorf("block %d changed while indexing", block-1)
+		}
+		hashes[block-1] = header.Hash()
+		first = header
+	}
+	// the range must extend the indexed blocks, the chain may have forked since the last batch
+	if from > ix.config.StartBlock {
+		if low != from {
+			if first, err = ix.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(from)); err != nil {
+				return err
+			}
+		}
+		parent, ok, err := readHash(ix.db, from-1)
+		if err != nil {
+			return err
+		}
+		if ok && first.ParentHash != parent {
+			return fmt.Errorf("%w: block %d is not a child of the indexed block %d", errForked, from, from-1)
+		}
+	}
+	logs, err := ix.chain.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{ix.config.Address},
+		Topics:    [][]common.Hash{{sendDACommitmentID}},
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	batch := ix.db.NewBatch()
+	counts := make(map[[32]byte]uint64)
+	undo := make(map[uint64][]byte)
+	for _, l := range logs {
+		if l.Removed || l.BlockNumber < from || l.BlockNumber > to {
+			continue
+		}
+		if hash, ok := hashes[l.BlockNumber]; ok && l.BlockHash != hash {
+			return fmt.Errorf("block %d changed while indexing", l.BlockNumber)
+		}
+		var ev contracts.SendDACommitment
+		if err := contracts.CommitmentManagerABI.UnpackIntoInterface(&ev, "SendDACommitment", l.Data); err != nil {
+			return fmt.Errorf("invalid SendDACommitment log %s/%d: %w", l.TxHash, l.Index, err)
+		}
+		hashes[l.BlockNumber] = l.BlockHash
+		if ev.NameSpaceKey == ([32]byte{}) {
+			continue
+		}
+		count, err := ix.count(counts, ev.NameSpaceKey)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(commitKey(ev.NameSpaceKey, count), encodeCommitment(ev.Commitment)); err != nil {
+			return err
+		}
+		counts[ev.NameSpaceKey] = count + 1
+		undo[l.BlockNumber] = append(undo[l.BlockNumber], ev.NameSpaceKey[:]...)
+	}
+	// the blocks below low are already too deep to be rolled back
+	for block, keys := range undo {
+		if block < low {
+			continue
+		}
+		if err := batch.Put(blockKey(undoPrefix, block), keys); err != nil {
+			return err
+		}
+	}
+	for block, hash := range hashes {
+		if block < low {
+			continue
+		}
+		if err := batch.Put(blockKey(hashPrefix, block), hash.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := writeCounts(batch, counts); err != nil {
+		return err
+	}
+	if err := ix.prune(batch, to+1); err != nil {
+		return err
+	}
+	if err := batch.Put(nextKey, encodeUint64(to+1)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// prune deletes the hashes and undo entries handleReorg no longer needs once next is the next
+// block to index.
+func (ix *Indexer) prune(batch ethdb.Batch, next uint64) error {
+	if next <= ix.config.ReorgDepth+1 {
+		return nil
+	}
+	limit := encodeUint64(next - ix.config.ReorgDepth - 1)
+	for _, prefix := range [][]byte{hashPrefix, undoPrefix} {
+		if err := prunePrefix(ix.db, batch, prefix, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prunePrefix(db ethdb.Iteratee, batch ethdb.Batch, prefix []byte, limit []byte) error {
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+	for it.Next() && bytes.Compare(it.Key()[len(prefix):], limit) < 0 {
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// count returns the number of commitments of the namespace, counts holding the pending ones.
+func (ix *Indexer) count(counts map[[32]byte]uint64, nameSpaceKey [32]byte) (uint64, error) {
+	if count, ok := counts[nameSpaceKey]; ok {
+		return count, nil
+	}
+	count, _, err := readUint64(ix.db, countKey(nameSpaceKey))
+	return count, err
+}
+
+func writeCounts(batch ethdb.Batch, counts map[[32]byte]uint64) error {
+	for nameSpaceKey, count := range counts {
+		if err := batch.Put(countKey(nameSpaceKey), encodeUint64(count)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	commitmentManagerAddress = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	nameSpaceA               = [32]byte{0xa}
+	nameSpaceB               = [32]byte{0xb}
+)
+
+// event is a SendDACommitment of a block of the fakeChain.
+type event struct {
+	nameSpaceKey [32]byte
+	commitment   kzg.Digest
+}
+
+// fakeChain is a chain of headers with SendDACommitment logs, forks differing by the Extra of
+// their headers.
+type fakeChain struct {
+	mu      sync.Mutex
+	headers []*types.Header
+	logs    map[common.Hash][]types.Log
+	fork    byte
+	nonce   int64
+	// onHeader, if set, is called before each HeaderByNumber of a block number
+	onHeader func(number uint64)
+}
+
+func newFakeChain() *fakeChain {
+	c := &fakeChain{logs: make(map[common.Hash][]types.Log)}
+	c.headers = []*types.Header{{Number: big.NewInt(0)}}
+	return c
+}
+
+// mine appends a block holding the events.
+func (c *fakeChain) mine(t *testing.T, events ...event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parent := c.headers[len(c.headers)-1]
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(int64(len(c.headers))), Extra: []byte{c.fork}}
+	c.headers = append(c.headers, header)
+	inputs := contracts.CommitmentManagerABI.Events["SendDACommitment"].Inputs
+	for i, ev := range events {
+		data, err := inputs.NonIndexed().Pack(contracts.G1PointFromDigest(&ev.commitment), big.NewInt(0),
+			big.NewInt(c.nonce), big.NewInt(0), big.NewInt(0), [32]byte{}, ev.nameSpaceKey, [][]byte{})
+		require.NoError(t, err)
+		c.nonce++
+		c.logs[header.Hash()] = append(c.logs[header.Hash()], types.Log{
+			Address:     commitmentManagerAddress,
+			Topics:      []common.Hash{sendDACommitmentID},
+			Data:        data,
+			BlockNumber: header.Number.Uint64(),
+			BlockHash:   header.Hash(),
+			Index:       uint(i),
+		})
+	}
+}
+
+// reorg drops the blocks from block on, the blocks mined next are on a new fork.
+func (c *fakeChain) reorg(block uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = c.headers[:block]
+	c.fork++
+}
+
+func (c *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number != nil && c.onHeader != nil {
+		c.onHeader(number.Uint64())
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number == nil {
+		return c.headers[len(c.headers)-1], nil
+	}
+	if number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func (c *fakeChain) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var logs []types.Log
+	for n := query.FromBlock.Uint64(); n <= query.ToBlock.Uint64() && n < uint64(len(c.headers)); n++ {
+		for _, l := range c.logs[c.headers[n].Hash()] {
+			if l.Address == query.Addresses[0] && l.Topics[0] == query.Topics[0][0] {
+				logs = append(logs, l)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func commitment(i int64) kzg.Digest {
+	_, _, g1, _ := bn254.Generators()
+	var d kzg.Digest
+	d.ScalarMultiplication(&g1, big.NewInt(i))
+	return d
+}
+
+func commitments(values ...int64) []kzg.Digest {
+	commits := make([]kzg.Digest, len(values))
+	for i, v := range values {
+		commits[i] = commitment(v)
+	}
+	return commits
+}
+
+func assertNameSpace(t *testing.T, ix *Indexer, nameSpaceKey [32]byte, expected []kzg.Digest) {
+	count, err := ix.NameSpaceIndex(nameSpaceKey)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(expected)), count)
+	if len(expected) == 0 {
+		return
+	}
+	commits, err := ix.CommitmentsForNamespace(nameSpaceKey, 0, count-1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, commits)
+}
+
+func TestIndexerSync(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain()
+	// events before the start block are not the CommitmentManager's
+	chain.mine(t, event{nameSpaceA, commitment(100)})
+	chain.mine(t)
+	chain.mine(t, event{nameSpaceA, commitment(1)}, event{nameSpaceB, commitment(2)}, event{nameSpaceA, commitment(3)})
+	chain.mine(t, event{[32]byte{}, commitment(4)})
+	chain.mine(t)
+	chain.mine(t, event{nameSpaceB, commitment(5)})
+	chain.mine(t, event{nameSpaceA, commitment(6)})
+
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, StartBlock: 2, BatchSize: 2, Confirmations: 1})
+	require.NoError(t, ix.Sync(ctx))
+	next, err := ix.NextBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), next)
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 3))
+	assertNameSpace(t, ix, nameSpaceB, commitments(2, 5))
+	assertNameSpace(t, ix, [32]byte{}, nil)
+
+	chain.mine(t, event{nameSpaceA, commitment(7)})
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 3, 6))
+
+	commits, err := ix.CommitmentsForNamespace(nameSpaceA, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, commitments(3, 6), commits)
+	_, err = ix.CommitmentsForNamespace(nameSpaceA, 1, 3)
+	assert.ErrorIs(t, err, ErrNotIndexed)
+	_, err = ix.CommitmentsForNamespace(nameSpaceB, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestIndexerReorg(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain()
+	for i := int64(1); i <= 6; i++ {
+		chain.mine(t, event{nameSpaceA, commitment(i)})
+		chain.mine(t)
+	}
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, BatchSize: 5})
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2, 3, 4, 5, 6))
+
+	// the blocks from 8 on, holding commitments 5 and 6, are replaced
+	chain.reorg(8)
+	chain.mine(t, event{nameSpaceB, commitment(10)}, event{nameSpaceA, commitment(11)})
+	chain.mine(t, event{nameSpaceA, commitment(12)})
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2, 3, 4, 11, 12))
+	assertNameSpace(t, ix, nameSpaceB, commitments(10))
+	next, err := ix.NextBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), next)
+
+	// a shorter chain, the head being below the last indexed block
+	chain.reorg(5)
+	chain.mine(t)
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2))
+	assertNameSpace(t, ix, nameSpaceB, nil)
+	next, err = ix.NextBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), next)
+
+	// everything replaced
+	chain.reorg(1)
+	chain.mine(t, event{nameSpaceB, commitment(20)})
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, nil)
+	assertNameSpace(t, ix, nameSpaceB, commitments(20))
+}
+
+func TestIndexerReorgTooDeep(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain()
+	for i := int64(1); i <= 10; i++ {
+		chain.mine(t, event{nameSpaceA, commitment(i)})
+	}
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, BatchSize: 1, ReorgDepth: 4})
+	require.NoError(t, ix.Sync(ctx))
+	// only the hashes of the last ReorgDepth blocks, and of their parent, are kept
+	blocks, err := blocksFrom(ix.db, hashPrefix, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{6, 7, 8, 9, 10}, blocks)
+	blocks, err = blocksFrom(ix.db, undoPrefix, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{6, 7, 8, 9, 10}, blocks)
+
+	chain.reorg(7)
+	chain.mine(t)
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2, 3, 4, 5, 6))
+
+	chain.reorg(3)
+	for i := 0; i < 8; i++ {
+		chain.mine(t)
+	}
+	assert.ErrorIs(t, ix.Sync(ctx), ErrReorgTooDeep)
+	assert.ErrorIs(t, ix.Run(ctx), ErrReorgTooDeep)
+}
+
+func TestIndexerShallowReorgAfterBatch(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain()
+	chain.mine(t, event{nameSpaceA, commitment(1)})
+	for i := 0; i < 299; i++ {
+		chain.mine(t)
+	}
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, ReorgDepth: 128})
+	require.NoError(t, ix.Sync(ctx))
+	// every block that a reorg of up to ReorgDepth blocks can replace is recorded, with its parent
+	blocks, err := blocksFrom(ix.db, hashPrefix, 0)
+	require.NoError(t, err)
+	require.Len(t, blocks, 129)
+	assert.Equal(t, uint64(172), blocks[0])
+
+	chain.reorg(299)
+	chain.mine(t, event{nameSpaceA, commitment(2)})
+	chain.mine(t)
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2))
+	next, err := ix.NextBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(301), next)
+}
+
+func TestIndexerReorgBetweenBatches(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain()
+	for i := int64(1); i <= 9; i++ {
+		chain.mine(t, event{nameSpaceA, commitment(i)})
+	}
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, BatchSize: 3})
+
+	// the blocks from 2 on are replaced once the first batch, [0, 2], is indexed
+	reorged := false
+	chain.onHeader = func(number uint64) {
+		if number == 5 && !reorged {
+			reorged = true
+			chain.reorg(2)
+			for i := int64(20); i < 28; i++ {
+				chain.mine(t, event{nameSpaceA, commitment(i)})
+			}
+		}
+	}
+	require.NoError(t, ix.Sync(ctx))
+	assert.True(t, reorged)
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 20, 21, 22, 23, 24, 25, 26, 27))
+	next, err := ix.NextBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), next)
+}
+
+func TestIndexerPersistence(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	chain := newFakeChain()
+	chain.mine(t, event{nameSpaceA, commitment(1)})
+	chain.mine(t, event{nameSpaceA, commitment(2)})
+	config := Config{Address: commitmentManagerAddress, StartBlock: 1}
+
+	db, err := OpenLevelDB(path)
+	require.NoError(t, err)
+	require.NoError(t, New(db, chain, config).Sync(ctx))
+	require.NoError(t, db.Close())
+
+	chain.reorg(2)
+	chain.mine(t, event{nameSpaceA, commitment(3)})
+	chain.mine(t, event{nameSpaceA, commitment(4)})
+	db, err = OpenLevelDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+	ix := New(db, chain, config)
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 2))
+	require.NoError(t, ix.Sync(ctx))
+	assertNameSpace(t, ix, nameSpaceA, commitments(1, 3, 4))
+}
+
+func TestIndexerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	chain := newFakeChain()
+	chain.mine(t, event{nameSpaceA, commitment(1)})
+	ix := New(memorydb.New(), chain, Config{Address: commitmentManagerAddress, PollInterval: 1})
+	done := make(chan error)
+	go func() { done <- ix.Run(ctx) }()
+	assert.Eventually(t, func() bool {
+		count, err := ix.NameSpaceIndex(nameSpaceA)
+		return err == nil && count == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
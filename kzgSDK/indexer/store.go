@@ -0,0 +1,104 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+)
+
+// Layout of the store:
+//
+//	next                     -> next block to index
+//	hash   || block          -> hash of an indexed block, kept ReorgDepth blocks
+//	undo   || block          -> namespace keys of the commitments of the block, kept ReorgDepth blocks
+//	count  || nameSpaceKey   -> nameSpaceIndex of the namespace
+//	commit || nameSpaceKey || index -> X || Y of the commitment at index
+var (
+	nextKey      = []byte("next")
+	hashPrefix   = []byte("hash")
+	undoPrefix   = []byte("undo")
+	countPrefix  = []byte("count")
+	commitPrefix = []byte("commit")
+)
+
+// OpenLevelDB opens, or creates, the LevelDB database at path to back an Indexer.
+func OpenLevelDB(path string) (ethdb.KeyValueStore, error) {
+	return leveldb.New(path, 16, 16, "", false)
+}
+
+func encodeUint64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func blockKey(prefix []byte, block uint64) []byte {
+	return append(append([]byte(nil), prefix...), encodeUint64(block)...)
+}
+
+func countKey(nameSpaceKey [32]byte) []byte {
+	return append(append([]byte(nil), countPrefix...), nameSpaceKey[:]...)
+}
+
+func commitKey(nameSpaceKey [32]byte, index uint64) []byte {
+	key := append(append([]byte(nil), commitPrefix...), nameSpaceKey[:]...)
+	return append(key, encodeUint64(index)...)
+}
+
+// readUint64 reads a value written with encodeUint64, ok is false if there is none.
+func readUint64(db ethdb.KeyValueReader, key []byte) (v uint64, ok bool, err error) {
+	has, err := db.Has(key)
+	if err != nil || !has {
+		return 0, false, err
+	}
+	value, err := db.Get(key)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(value) != 8 {
+		return 0, false, fmt.Errorf("corrupted value of %x", key)
+	}
+	return binary.BigEndian.Uint64(value), true, nil
+}
+
+func readHash(db ethdb.KeyValueReader, block uint64) (common.Hash, bool, error) {
+	key := blockKey(hashPrefix, block)
+	has, err := db.Has(key)
+	if err != nil || !has {
+		return common.Hash{}, false, err
+	}
+	value, err := db.Get(key)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	return common.BytesToHash(value), true, nil
+}
+
+func encodeCommitment(p contracts.G1Point) []byte {
+	value := make([]byte, 64)
+	p.X.FillBytes(value[:32])
+	p.Y.FillBytes(value[32:])
+	return value
+}
+
+func decodeCommitment(value []byte) (kzg.Digest, error) {
+	if len(value) != 64 {
+		return kzg.Digest{}, fmt.Errorf("corrupted commitment of length %d", len(value))
+	}
+	return contracts.G1Point{X: new(big.Int).SetBytes(value[:32]), Y: new(big.Int).SetBytes(value[32:])}.ToDigest()
+}
+
+// blocksFrom returns the block numbers of the keys with the given prefix, from start on, in ascending order.
+func blocksFrom(db ethdb.Iteratee, prefix []byte, start uint64) ([]uint64, error) {
+	it := db.NewIterator(prefix, encodeUint64(start))
+	defer it.Release()
+	var blocks []uint64
+	for it.Next() {
+		blocks = append(blocks, binary.BigEndian.Uint64(it.Key()[len(prefix):]))
+	}
+	return blocks, it.Error()
+}
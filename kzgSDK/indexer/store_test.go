@@ -0,0 +1,49 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreEncoding(t *testing.T) {
+	for _, i := range []int64{0, 1, 12345} {
+		d := commitment(i)
+		value := encodeCommitment(contracts.G1PointFromDigest(&d))
+		assert.Len(t, value, 64)
+		decoded, err := decodeCommitment(value)
+		require.NoError(t, err)
+		assert.Equal(t, d, decoded)
+	}
+	_, err := decodeCommitment(make([]byte, 63))
+	assert.Error(t, err)
+	offCurve := make([]byte, 64)
+	offCurve[31], offCurve[63] = 1, 1
+	_, err = decodeCommitment(offCurve)
+	assert.ErrorIs(t, err, contracts.ErrInvalidG1Point)
+
+	db := memorydb.New()
+	_, ok, err := readUint64(db, nextKey)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, db.Put(nextKey, encodeUint64(1<<40)))
+	v, ok, err := readUint64(db, nextKey)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<40), v)
+	require.NoError(t, db.Put(nextKey, []byte{1}))
+	_, _, err = readUint64(db, nextKey)
+	assert.Error(t, err)
+
+	// block keys sort by block number
+	for _, block := range []uint64{256, 1, 65536, 2} {
+		require.NoError(t, db.Put(blockKey(hashPrefix, block), []byte{1}))
+	}
+	require.NoError(t, db.Put(blockKey(undoPrefix, 3), []byte{1}))
+	blocks, err := blocksFrom(db, hashPrefix, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 256, 65536}, blocks)
+}
@@ -0,0 +1,145 @@
+package kzgsdk
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+var (
+	// ErrDomainSize is returned when a domain size is not a power of two or does not fit in the srs.
+	ErrDomainSize = errors.New("invalid evaluation domain size")
+	// ErrDomainIndex is returned when opening outside of the evaluation domain.
+	ErrDomainIndex = errors.New("index is outside of the evaluation domain")
+)
+
+// LagrangeSRS is the Lagrange basis of an srs over the domain {ωⁱ} of n-th roots of unity.
+// A vector of evaluations (f(ω⁰), ..., f(ωⁿ⁻¹)) is committed as ∑ᵢf(ωⁱ)Lᵢ(τ)G1, which is the
+// commitment of the polynomial f in monomial form. When the output of DataToPolynomial is used as
+// evaluations, the header is f(ω⁰) and chunk k of the blob is f(ωᵏ⁺¹), proven with a single opening.
+type LagrangeSRS struct {
+	Domain *fft.Domain
+	// G1[i] = Lᵢ(τ)G1
+	G1 []bn254.G1Affine
+	// Pk is the monomial proving key used to compute the opening proofs.
+	Pk kzg.ProvingKey
+}
+
+// NewLagrangeSRS derives the Lagrange basis of the first size powers of srs with an inverse FFT in G1:
+// Lᵢ(τ)G1 = 1/n ∑ⱼω⁻ⁱʲτʲG1.
+// size must be a power of two no larger than the srs.
+func NewLagrangeSRS(srs *kzg.SRS, size uint64) (*LagrangeSRS, error) {
+	if size < 2 || size != ecc.NextPowerOfTwo(size) || size > uint64(len(srs.Pk.G1)) {
+		return nil, fmt.Errorf("%w: %d, srs size %d", ErrDomainSize, size, len(srs.Pk.G1))
+	}
+	domain := fft.NewDomain(size)
+
+	points := make([]bn254.G1Jac, size)
+	for i := range points {
+		points[i].FromAffine(&srs.Pk.G1[i])
+	}
+	fftG1(points, domain.GeneratorInv)
+	var cardinalityInv big.Int
+	domain.CardinalityInv.BigInt(&cardinalityInv)
+	for i := range points {
+		points[i].ScalarMultiplication(&points[i], &cardinalityInv)
+	}
+
+	return &LagrangeSRS{
+		Domain: domain,
+		G1:     bn254.BatchJacobianToAffineG1(points),
+		Pk:     kzg.ProvingKey{G1: srs.Pk.G1[:size]},
+	}, nil
+}
+
+// Point returns ωⁱ, the domain point holding evaluation i.
+func (l *LagrangeSRS) Point(index uint64) fr.Element {
+	var point fr.Element
+	point.Exp(l.Domain.Generator, new(big.Int).SetUint64(index))
+	return point
+}
+
+// Commit computes the KZG commitment of evaluations over the domain, missing evaluations are zero.
+func (l *LagrangeSRS) Commit(evaluations []fr.Element) (kzg.Digest, error) {
+	if len(evaluations) == 0 || len(evaluations) > len(l.G1) {
+		return kzg.Digest{}, fmt.Errorf("%w: %d evaluations, domain size %d", ErrDomainSize, len(evaluations), len(l.G1))
+	}
+	var digest kzg.Digest
+	_, err := digest.MultiExp(l.G1[:len(evaluations)], evaluations, ecc.MultiExpConfig{})
+	return digest, err
+}
+
+// Open computes the opening proof of evaluations at ωⁱ, the claimed value is evaluations[index].
+func (l *LagrangeSRS) Open(evaluations []fr.Element, index uint64) (kzg.OpeningProof, error) {
+	if index >= l.Domain.Cardinality {
+		return kzg.OpeningProof{}, fmt.Errorf("%w: %d", ErrDomainIndex, index)
+	}
+	polynomial, err := l.Interpolate(evaluations)
+	if err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return kzg.Open(polynomial, l.Point(index), l.Pk)
+}
+
+// Verify checks that proof opens commit to proof.ClaimedValue at the domain point ωⁱ.
+func (l *LagrangeSRS) Verify(commit *kzg.Digest, proof *kzg.OpeningProof, index uint64, vk kzg.VerifyingKey) error {
+	if index >= l.Domain.Cardinality {
+		return fmt.Errorf("%w: %d", ErrDomainIndex, index)
+	}
+	return kzg.Verify(commit, proof, l.Point(index), vk)
+}
+
+// Interpolate returns the coefficients of the polynomial taking the given evaluations over the domain.
+func (l *LagrangeSRS) Interpolate(evaluations []fr.Element) ([]fr.Element, error) {
+	if len(evaluations) == 0 || uint64(len(evaluations)) > l.Domain.Cardinality {
+		return nil, fmt.Errorf("%w: %d evaluations, domain size %d", ErrDomainSize, len(evaluations), l.Domain.Cardinality)
+	}
+	polynomial := make([]fr.Element, l.Domain.Cardinality)
+	copy(polynomial, evaluations)
+	l.Domain.FFTInverse(polynomial, fft.DIF)
+	fft.BitReverse(polynomial)
+	return polynomial, nil
+}
+
+// fftG1 computes in place the FFT of points over the domain generated by w, len(points) must be a power of two.
+func fftG1(points []bn254.G1Jac, w fr.Element) {
+	n := len(points)
+	shift := 64 - bits.TrailingZeros64(uint64(n))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if i < j {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+
+	twiddles := make([]big.Int, n/2)
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		var step, twiddle fr.Element
+		step.Exp(w, big.NewInt(int64(n/size)))
+		twiddle.SetOne()
+		for j := 0; j < half; j++ {
+			twiddle.BigInt(&twiddles[j])
+			twiddle.Mul(&twiddle, &step)
+		}
+		for start := 0; start < n; start += size {
+			for j := 0; j < half; j++ {
+				var t bn254.G1Jac
+				t.Set(&points[start+j+half])
+				if j != 0 {
+					t.ScalarMultiplication(&t, &twiddles[j])
+				}
+				u := points[start+j]
+				points[start+j].Set(&u).AddAssign(&t)
+				points[start+j+half].Set(&u).SubAssign(&t)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package kzgsdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLagrangeSRS(t *testing.T) {
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	lagrange, err := NewLagrangeSRS(srs, 128)
+	assert.NoError(t, err)
+
+	// Lᵢ(τ)G1 is the commitment of the polynomial equal to 1 at ωⁱ and 0 elsewhere
+	for _, i := range []int{0, 1, 77, 127} {
+		evaluations := make([]fr.Element, 128)
+		evaluations[i].SetOne()
+		polynomial, err := lagrange.Interpolate(evaluations)
+		assert.NoError(t, err)
+		commit, err := kzg.Commit(polynomial, srs.Pk)
+		assert.NoError(t, err)
+		assert.True(t, commit.Equal(&lagrange.G1[i]), "L_%d", i)
+	}
+
+	evaluations := randomPolynomial(100)
+	commit, err := lagrange.Commit(evaluations)
+	assert.NoError(t, err)
+	polynomial, err := lagrange.Interpolate(evaluations)
+	assert.NoError(t, err)
+	monomialCommit, err := kzg.Commit(polynomial, srs.Pk)
+	assert.NoError(t, err)
+	assert.Equal(t, monomialCommit, commit)
+
+	for _, i := range []uint64{0, 5, 99, 100, 127} {
+		proof, err := lagrange.Open(evaluations, i)
+		assert.NoError(t, err)
+		if i < 100 {
+			assert.Equal(t, evaluations[i], proof.ClaimedValue)
+		} else {
+			assert.True(t, proof.ClaimedValue.IsZero())
+		}
+		assert.NoError(t, lagrange.Verify(&commit, &proof, i, srs.Vk))
+		// the proof does not hold for another chunk
+		assert.Error(t, lagrange.Verify(&commit, &proof, (i+1)%128, srs.Vk))
+	}
+
+	_, err = lagrange.Open(evaluations, 128)
+	assert.ErrorIs(t, err, ErrDomainIndex)
+	_, err = lagrange.Commit(randomPolynomial(129))
+	assert.ErrorIs(t, err, ErrDomainSize)
+}
+
+func TestLagrangeSRSChunk(t *testing.T) {
+	generated, err := kzg.NewSRS(256, big.NewInt(42))
+	assert.NoError(t, err)
+	lagrange, err := NewLagrangeSRS(generated, 256)
+	assert.NoError(t, err)
+
+	data := []byte("Broadcast nodes calculate the values of sampling points and Providing corresponding values and proof.")
+	evaluations := DataToPolynomial(data)
+	commit, err := lagrange.Commit(evaluations)
+	assert.NoError(t, err)
+
+	// prove the second 30-byte chunk of data, it is held by evaluation 2 after the header
+	proof, err := lagrange.Open(evaluations, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, lagrange.Verify(&commit, &proof, 2, generated.Vk))
+	chunk, err := elementToChunk(&proof.ClaimedValue)
+	assert.NoError(t, err)
+	assert.Equal(t, data[dChunkSize:2*dChunkSize], chunk[:])
+
+	for _, size := range []uint64{0, 1, 3, 512} {
+		_, err = NewLagrangeSRS(generated, size)
+		assert.ErrorIs(t, err, ErrDomainSize)
+	}
+}
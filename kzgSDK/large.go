@@ -0,0 +1,143 @@
+package kzgsdk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ErrManifestMismatch is returned when blobs do not match the manifest they are reassembled with.
+var ErrManifestMismatch = errors.New("blobs do not match the manifest")
+
+// manifestHeaderLen is the length of the fixed part of an encoded Manifest: length, blob size,
+// first index and count.
+const manifestHeaderLen = 4 * 8
+
+// Manifest describes a payload split into blobs by CommitLarge.
+// Commitments[i] is the commitment of blob i, blobs are meant to be submitted to the
+// CommitmentManager one after the other, so blob i ends up at nameSpaceCommitments index FirstIndex+i.
+type Manifest struct {
+	// Length is the length of the whole payload.
+	Length uint64
+	// BlobSize is the number of payload bytes in every blob but the last one.
+	BlobSize uint64
+	// FirstIndex is the nameSpaceCommitments index of the first blob.
+	FirstIndex uint64
+	// Commitments of the blobs, in payload order.
+	Commitments []kzg.Digest
+}
+
+// MaxBlobData returns the largest payload that fits in a single blob committed with srs:
+// one coefficient holds the encoding header and each of the others a 30-byte chunk.
+func MaxBlobData(srs *kzg.SRS) uint64 {
+	if len(srs.Pk.G1) < 2 {
+		return 0
+	}
+	return uint64(len(srs.Pk.G1)-1) * dChunkSize
+}
+
+// SplitData splits data into blobs of blobSize bytes, the last one holding the remainder.
+// Empty data is a single empty blob.
+func SplitData(data []byte, blobSize uint64) [][]byte {
+	if len(data) == 0 {
+		return [][]byte{{}}
+	}
+	return chunkBytes(data, int(blobSize))
+}
+
+// CommitLarge splits data into the largest blobs the srs can commit, see MaxBlobData, and commits
+// each of them, the first blob to be submitted at the nameSpaceCommitments index firstIndex. It
+// returns the manifest of the payload and the blobs, in the same order as the manifest.
+func (sdk *DomiconSdk) CommitLarge(data []byte, firstIndex uint64) (Manifest, [][]byte, error) {
+	blobSize := MaxBlobData(sdk.srs)
+	if blobSize == 0 {
+		return Manifest{}, nil, fmt.Errorf("%w: srs size %d", ErrPolynomialTooLarge, len(sdk.srs.Pk.G1))
+	}
+	blobs := SplitData(data, blobSize)
+	manifest := Manifest{
+		Length:      uint64(len(data)),
+		BlobSize:    blobSize,
+		FirstIndex:  firstIndex,
+		Commitments: make([]kzg.Digest, len(blobs)),
+	}
+	for i, blob := range blobs {
+		commit, err := sdk.CommitData(blob)
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+		manifest.Commitments[i] = commit
+	}
+	return manifest, blobs, nil
+}
+
+// Reassemble checks every blob against its commitment in manifest and joins them back into the
+// payload, blobs being those read from the nameSpaceCommitments index firstIndex on.
+func (sdk *DomiconSdk) Reassemble(manifest Manifest, firstIndex uint64, blobs [][]byte) ([]byte, error) {
+	if firstIndex != manifest.FirstIndex {
+		return nil, fmt.Errorf("%w: blobs from index %d, manifest from %d", ErrManifestMismatch, firstIndex, manifest.FirstIndex)
+	}
+	if len(blobs) != len(manifest.Commitments) {
+		return nil, fmt.Errorf("%w: %d blobs, %d commitments", ErrManifestMismatch, len(blobs), len(manifest.Commitments))
+	}
+	data := make([]byte, 0, manifest.Length)
+	for i, blob := range blobs {
+		last := i == len(blobs)-1
+		if (!last && uint64(len(blob)) != manifest.BlobSize) || (last && uint64(len(blob)) > manifest.BlobSize) {
+			return nil, fmt.Errorf("%w: blob %d has %d bytes, blob size %d", ErrManifestMismatch, i, len(blob), manifest.BlobSize)
+		}
+		commit, err := sdk.CommitData(blob)
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+		if !commit.Equal(&manifest.Commitments[i]) {
+			return nil, fmt.Errorf("%w: commitment of blob %d", ErrManifestMismatch, i)
+		}
+		data = append(data, blob...)
+	}
+	if uint64(len(data)) != manifest.Length {
+		return nil, fmt.Errorf("%w: %d bytes, manifest length %d", ErrManifestMismatch, len(data), manifest.Length)
+	}
+	return data, nil
+}
+
+// MarshalBinary encodes the manifest as length || blob size || first index || count (8 bytes
+// each, big endian) followed by the compressed commitments.
+func (m Manifest) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, manifestHeaderLen, manifestHeaderLen+len(m.Commitments)*bn254.SizeOfG1AffineCompressed)
+	binary.BigEndian.PutUint64(buf[0:], m.Length)
+	binary.BigEndian.PutUint64(buf[8:], m.BlobSize)
+	binary.BigEndian.PutUint64(buf[16:], m.FirstIndex)
+	binary.BigEndian.PutUint64(buf[24:], uint64(len(m.Commitments)))
+	for i := range m.Commitments {
+		b := m.Commitments[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a manifest encoded by MarshalBinary.
+func (m *Manifest) UnmarshalBinary(data []byte) error {
+	if len(data) < manifestHeaderLen {
+		return fmt.Errorf("%w: truncated manifest", ErrInvalidEncoding)
+	}
+	count := binary.BigEndian.Uint64(data[24:])
+	points := data[manifestHeaderLen:]
+	if uint64(len(points))%bn254.SizeOfG1AffineCompressed != 0 ||
+		uint64(len(points))/bn254.SizeOfG1AffineCompressed != count {
+		return fmt.Errorf("%w: %d bytes for %d commitments", ErrInvalidEncoding, len(points), count)
+	}
+	commitments := make([]kzg.Digest, count)
+	for i := range commitments {
+		if _, err := commitments[i].SetBytes(points[i*bn254.SizeOfG1AffineCompressed:]); err != nil {
+			return fmt.Errorf("%w: commitment %d: %v", ErrInvalidEncoding, i, err)
+		}
+	}
+	m.Length = binary.BigEndian.Uint64(data[0:])
+	m.BlobSize = binary.BigEndian.Uint64(data[8:])
+	m.FirstIndex = binary.BigEndian.Uint64(data[16:])
+	m.Commitments = commitments
+	return nil
+}
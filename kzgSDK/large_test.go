@@ -0,0 +1,83 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitLarge(t *testing.T) {
+	sdk, err := NewDomiconSdkFromSol()
+	assert.NoError(t, err)
+	blobSize := MaxBlobData(sdk.SRS())
+	assert.Equal(t, uint64(3840), blobSize)
+
+	for i, length := range []uint64{0, 1, blobSize, blobSize + 1, 3*blobSize - 7} {
+		firstIndex := uint64(i * 1000)
+		data := make([]byte, length)
+		_, err := rand.Read(data)
+		assert.NoError(t, err)
+
+		manifest, blobs, err := sdk.CommitLarge(data, firstIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, length, manifest.Length)
+		assert.Equal(t, firstIndex, manifest.FirstIndex)
+		assert.Equal(t, len(blobs), len(manifest.Commitments))
+		for i, blob := range blobs {
+			commit, err := sdk.CommitData(blob)
+			assert.NoError(t, err)
+			assert.Equal(t, manifest.Commitments[i], commit)
+		}
+
+		encoded, err := manifest.MarshalBinary()
+		assert.NoError(t, err)
+		var decoded Manifest
+		assert.NoError(t, decoded.UnmarshalBinary(encoded))
+		assert.Equal(t, manifest, decoded)
+
+		reassembled, err := sdk.Reassemble(decoded, firstIndex, blobs)
+		assert.NoError(t, err)
+		assert.True(t, bytes.Equal(data, reassembled))
+	}
+}
+
+func TestReassembleErrors(t *testing.T) {
+	sdk, err := NewDomiconSdkFromSol()
+	assert.NoError(t, err)
+	data := make([]byte, 2*MaxBlobData(sdk.SRS())+100)
+	_, err = rand.Read(data)
+	assert.NoError(t, err)
+	manifest, blobs, err := sdk.CommitLarge(data, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(blobs))
+	_, err = sdk.Reassemble(manifest, 5, blobs)
+	assert.NoError(t, err)
+
+	// missing blob
+	_, err = sdk.Reassemble(manifest, 5, blobs[:2])
+	assert.ErrorIs(t, err, ErrManifestMismatch)
+	// blobs out of order
+	_, err = sdk.Reassemble(manifest, 5, [][]byte{blobs[1], blobs[0], blobs[2]})
+	assert.ErrorIs(t, err, ErrManifestMismatch)
+	// corrupted blob
+	corrupted := append([]byte{}, blobs[2]...)
+	corrupted[0] ^= 1
+	_, err = sdk.Reassemble(manifest, 5, [][]byte{blobs[0], blobs[1], corrupted})
+	assert.ErrorIs(t, err, ErrManifestMismatch)
+	// wrong length
+	wrong := manifest
+	wrong.Length++
+	_, err = sdk.Reassemble(wrong, 5, blobs)
+	assert.ErrorIs(t, err, ErrManifestMismatch)
+	// blobs read from another index
+	_, err = sdk.Reassemble(manifest, 6, blobs)
+	assert.ErrorIs(t, err, ErrManifestMismatch)
+
+	encoded, err := manifest.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded Manifest
+	assert.ErrorIs(t, decoded.UnmarshalBinary(encoded[:10]), ErrInvalidEncoding)
+	assert.ErrorIs(t, decoded.UnmarshalBinary(encoded[:len(encoded)-1]), ErrInvalidEncoding)
+}
@@ -0,0 +1,259 @@
+package kzgsdk
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ParallelConfig configures the parallel versions of the folding and commitment functions.
+type ParallelConfig struct {
+	// Workers is the number of goroutines sharing the work, runtime.NumCPU() if zero.
+	Workers int
+	// NbTasks is the ecc.MultiExpConfig NbTasks of each multi-exponentiation. If zero, the
+	// multi-exponentiations of CommitMany share the CPUs with the workers, and the others use
+	// the gnark-crypto default.
+	NbTasks int
+}
+
+func (c ParallelConfig) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// parallelize calls work on n items split into contiguous chunks [start, end), one per worker.
+func parallelize(n int, workers int, work func(start, end int)) {
+	workers = min(workers, n)
+	if workers <= 1 {
+		if n > 0 {
+			work(0, n)
+		}
+		return
+	}
+	chunk, rest := n/workers, n%workers
+	var wg sync.WaitGroup
+	start := 0
+	for i := 0; i < workers; i++ {
+		end := start + chunk
+		if i < rest {
+			end++
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			work(start, end)
+		}(start, end)
+		start = end
+	}
+	wg.Wait()
+}
+
+// CoefficientsParallel computes r_start, ..., r_end of Coefficients with the workers of config.
+func (s FoldSeed) CoefficientsParallel(start uint64, end uint64, config ParallelConfig) []fr.Element {
+	if start > end {
+		return nil
+	}
+	coefficients := make([]fr.Element, end-start+1)
+	parallelize(len(coefficients), config.workers(), func(from, to int) {
+		for i := from; i < to; i++ {
+			coefficients[i] = s.Coefficient(start + uint64(i))
+		}
+	})
+	return coefficients
+}
+
+// AggregateRangeParallel computes CM_{start,end} of AggregateRange, generating the coefficients
+// with the workers of config and using its NbTasks for the multi-exponentiation.
+func AggregateRangeParallel(commits []kzg.Digest, seed FoldSeed, start uint64, end uint64, config ParallelConfig) (kzg.Digest, error) {
+	if err := checkRange(start, end, len(commits)); err != nil {
+		return kzg.Digest{}, err
+	}
+	var aggregate kzg.Digest
+	coefficients := seed.CoefficientsParallel(start, end, config)
+	_, err := aggregate.MultiExp(commits[start:end+1], coefficients, ecc.MultiExpConfig{NbTasks: config.NbTasks})
+	return aggregate, err
+}
+
+// AggregatePolynomialsParallel computes the polynomial of AggregatePolynomials with the workers
+// of config.
+func AggregatePolynomialsParallel(polynomials [][]fr.Element, seed FoldSeed, start uint64, end uint64, config ParallelConfig) ([]fr.Element, error) {
+	if err := checkRange(start, end, len(polynomials)); err != nil {
+		return nil, err
+	}
+	return aggregatePolynomialsParallel(polynomials[start:end+1], seed, start, config), nil
+}
+
+// aggregatePolynomialsParallel is aggregatePolynomials with the workers of config, each of them
+// summing a share of the coefficients of the folded polynomial.
+func aggregatePolynomialsParallel(polynomials [][]fr.Element, seed FoldSeed, start uint64, config ParallelConfig) []fr.Element {
+	if len(polynomials) == 0 {
+		return nil
+	}
+	coefficients := seed.CoefficientsParallel(start, start+uint64(len(polynomials))-1, config)
+	largestPoly := 0
+	for i := range polynomials {
+		largestPoly = max(largestPoly, len(polynomials[i]))
+	}
+	aggregate := make([]fr.Element, largestPoly)
+	parallelize(largestPoly, config.workers(), func(from, to int) {
+		var pj fr.Element
+		for i := range polynomials {
+			polynomial := polynomials[i][min(from, len(polynomials[i])):min(to, len(polynomials[i]))]
+			for j := range polynomial {
+				pj.Mul(&polynomial[j], &coefficients[i])
+				aggregate[from+j].Add(&aggregate[from+j], &pj)
+			}
+		}
+	})
+	return aggregate
+}
+
+// OpenRangeFromParallel computes the opening proof of OpenRangeFrom, folding the blobs with the
+// workers of config.
+func (sdk *DomiconSdk) OpenRangeFromParallel(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	seed FoldSeed,
+	start uint64,
+	config ParallelConfig,
+) (kzg.OpeningProof, error) {
+	if len(polynomials) == 0 {
+		return kzg.OpeningProof{}, fmt.Errorf("%w: no blob from %d", ErrInvalidRange, start)
+	}
+	aggregate := aggregatePolynomialsParallel(polynomials, seed, start, config)
+	if err := checkPolynomials([][]fr.Element{aggregate}, sdk.srs); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return kzg.Open(aggregate, openPoint, sdk.srs.Pk)
+}
+
+// AddParallel folds the blobs of polynomials, polynomials[k] being the blob at namespace index
+// start+k, with the workers of config. It returns ErrPolynomialTooLarge if a blob does not fit
+// in the srs, leaving the polynomial unchanged.
+func (f *Folder) AddParallel(start uint64, polynomials [][]fr.Element, config ParallelConfig) error {
+	for k := range polynomials {
+		if len(polynomials[k]) > len(f.srs.Pk.G1) {
+			return fmt.Errorf("%w: %d coefficients at index %d, srs size %d", ErrPolynomialTooLarge, len(polynomials[k]), start+uint64(k), len(f.srs.Pk.G1))
+		}
+	}
+	aggregate := aggregatePolynomialsParallel(polynomials, f.seed, start, config)
+	if len(aggregate) > len(f.folded) {
+		f.folded = append(f.folded, make([]fr.Element, len(aggregate)-len(f.folded))...)
+	}
+	parallelize(len(aggregate), config.workers(), func(from, to int) {
+		for j := from; j < to; j++ {
+			f.folded[j].Add(&f.folded[j], &aggregate[j])
+		}
+	})
+	f.count += len(polynomials)
+	return nil
+}
+
+// The functions below are the parallel versions of the gamma API, folding with the indices of
+// the slices they are given rather than with the namespace indices of the ChallengeContract.
+
+// GetRandomsHashParallel computes the hashes of GetRandomsHash with the workers of config.
+func GetRandomsHashParallel(gamma fr.Element, from uint, to uint, config ParallelConfig) []common.Hash {
+	if from >= to {
+		return []common.Hash{}
+	}
+	gammas := make([]common.Hash, to-from)
+	parallelize(len(gammas), config.workers(), func(start, end int) {
+		for i := start; i < end; i++ {
+			gammas[i] = GetRandomHash(gamma, from+uint(i))
+		}
+	})
+	return gammas
+}
+
+// FoldCoefficientsParallel computes r_start, ..., r_end of FoldCoefficients with the workers of config.
+func FoldCoefficientsParallel(gamma fr.Element, start uint64, end uint64, config ParallelConfig) []fr.Element {
+	return GammaSeed(gamma).CoefficientsParallel(start, end, config)
+}
+
+// FoldedPolynomialsParallel computes the polynomial of FoldedPolynomials with the workers of
+// config, each of them summing a share of the coefficients of the folded polynomial.
+func FoldedPolynomialsParallel(
+	polynomials [][]fr.Element,
+	gamma fr.Element,
+	config ParallelConfig,
+) []fr.Element {
+	return aggregatePolynomialsParallel(polynomials, GammaSeed(gamma), 0, config)
+}
+
+// TryResponceParallel generates the opening proof of TryResponce, folding the polynomials with
+// FoldedPolynomialsParallel.
+func TryResponceParallel(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	gamma fr.Element,
+	srs *kzg.SRS,
+	config ParallelConfig,
+) (kzg.OpeningProof, error) {
+	if err := checkPolynomials(polynomials, srs); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	return kzg.Open(FoldedPolynomialsParallel(polynomials, gamma, config), openPoint, srs.Pk)
+}
+
+// FoldedCommitsParallel computes the commitment of FoldedCommits, generating the coefficients
+// with the workers of config and using its NbTasks for the multi-exponentiation.
+func FoldedCommitsParallel(
+	Commits []kzg.Digest,
+	gamma fr.Element,
+	from uint,
+	to uint,
+	config ParallelConfig,
+) (kzg.Digest, error) {
+	if from >= to || to > uint(len(Commits)) {
+		return FoldedCommits(Commits, gamma, from, to)
+	}
+	var AggreCommit kzg.Digest
+	gammas := FoldCoefficientsParallel(gamma, uint64(from), uint64(to-1), config)
+	_, err := AggreCommit.MultiExp(Commits[from:to], gammas, ecc.MultiExpConfig{NbTasks: config.NbTasks})
+	return AggreCommit, err
+}
+
+// CommitMany computes the KZG commitments of polynomials, committing several of them at once
+// with the workers of config. It returns the error of the first polynomial that failed.
+func (sdk *DomiconSdk) CommitMany(polynomials [][]fr.Element, config ParallelConfig) ([]kzg.Digest, error) {
+	workers := config.workers()
+	nbTasks := config.NbTasks
+	if nbTasks == 0 {
+		nbTasks = max(1, runtime.NumCPU()/min(workers, max(len(polynomials), 1)))
+	}
+	commits := make([]kzg.Digest, len(polynomials))
+	failures := make([]error, len(polynomials))
+	parallelize(len(polynomials), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			commits[i], failures[i] = kzg.Commit(polynomials[i], sdk.srs.Pk, nbTasks)
+			if failures[i] != nil {
+				return
+			}
+		}
+	})
+	for _, err := range failures {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+// CommitManyData encodes each data with DataToPolynomial and commits them with CommitMany.
+func (sdk *DomiconSdk) CommitManyData(datas [][]byte, config ParallelConfig) ([]kzg.Digest, error) {
+	polynomials := make([][]fr.Element, len(datas))
+	parallelize(len(datas), config.workers(), func(start, end int) {
+		for i := start; i < end; i++ {
+			polynomials[i] = DataToPolynomial(datas[i])
+		}
+	})
+	return sdk.CommitMany(polynomials, config)
+}
@@ -0,0 +1,195 @@
+package kzgsdk
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallel(t *testing.T) {
+	srs, err := kzg.NewSRS(64, big.NewInt(42))
+	require.NoError(t, err)
+	sdk := NewDomiconSdk(srs)
+	const n = 37
+	polys := make([][]fr.Element, n)
+	for i := range polys {
+		polys[i] = randomPolynomial(1 + (i*13)%64)
+	}
+	var gamma, point fr.Element
+	gamma.SetRandom()
+	point.SetRandom()
+
+	commits := make([]kzg.Digest, n)
+	for i := range polys {
+		commits[i], err = sdk.Commit(polys[i])
+		require.NoError(t, err)
+	}
+	folded := FoldedPolynomials(polys, gamma)
+	proof, err := TryResponce(polys, point, gamma, srs)
+	require.NoError(t, err)
+	aggregate, err := FoldedCommits(commits, gamma, 3, n)
+	require.NoError(t, err)
+
+	for _, config := range []ParallelConfig{{}, {Workers: 1}, {Workers: 4, NbTasks: 2}, {Workers: 2 * n}} {
+		assert.Equal(t, GetRandomsHash(gamma, 5, 30), GetRandomsHashParallel(gamma, 5, 30, config))
+		assert.Empty(t, GetRandomsHashParallel(gamma, 5, 5, config))
+		assert.Equal(t, FoldCoefficients(gamma, 2, 40), FoldCoefficientsParallel(gamma, 2, 40, config))
+		assert.Nil(t, FoldCoefficientsParallel(gamma, 3, 2, config))
+
+		assert.Equal(t, folded, FoldedPolynomialsParallel(polys, gamma, config))
+		parallelProof, err := TryResponceParallel(polys, point, gamma, srs, config)
+		require.NoError(t, err)
+		assert.Equal(t, proof, parallelProof)
+
+		parallelAggregate, err := FoldedCommitsParallel(commits, gamma, 3, n, config)
+		require.NoError(t, err)
+		assert.Equal(t, aggregate, parallelAggregate)
+		_, err = FoldedCommitsParallel(commits, gamma, 3, 3, config)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		parallelCommits, err := sdk.CommitMany(polys, config)
+		require.NoError(t, err)
+		assert.Equal(t, commits, parallelCommits)
+	}
+
+	datas := [][]byte{[]byte("first"), []byte("second blob"), {}}
+	dataCommits, err := sdk.CommitManyData(datas, ParallelConfig{Workers: 2})
+	require.NoError(t, err)
+	for i, data := range datas {
+		commit, err := sdk.CommitData(data)
+		require.NoError(t, err)
+		assert.Equal(t, commit, dataCommits[i])
+	}
+
+	_, err = sdk.CommitMany([][]fr.Element{polys[0], randomPolynomial(65)}, ParallelConfig{})
+	assert.Error(t, err)
+	_, err = TryResponceParallel(nil, point, gamma, srs, ParallelConfig{})
+	assert.ErrorIs(t, err, ErrEmptyInput)
+}
+
+func TestParallelSeed(t *testing.T) {
+	srs, err := kzg.NewSRS(64, big.NewInt(42))
+	require.NoError(t, err)
+	sdk := NewDomiconSdk(srs)
+	const n = 37
+	polys := make([][]fr.Element, n)
+	for i := range polys {
+		polys[i] = randomPolynomial(1 + (i*13)%64)
+	}
+	commits, err := sdk.CommitMany(polys, ParallelConfig{})
+	require.NoError(t, err)
+	seed := randomSeed()
+	var point fr.Element
+	point.SetRandom()
+
+	aggregate, err := AggregateRange(commits, seed, 3, 30)
+	require.NoError(t, err)
+	folded, err := AggregatePolynomials(polys, seed, 3, 30)
+	require.NoError(t, err)
+	proof, err := sdk.OpenRange(polys, point, seed, 3, 30)
+	require.NoError(t, err)
+
+	for _, config := range []ParallelConfig{{}, {Workers: 1}, {Workers: 4, NbTasks: 2}, {Workers: 2 * n}} {
+		assert.Equal(t, seed.Coefficients(2, 40), seed.CoefficientsParallel(2, 40, config))
+		assert.Nil(t, seed.CoefficientsParallel(3, 2, config))
+
+		parallelAggregate, err := AggregateRangeParallel(commits, seed, 3, 30, config)
+		require.NoError(t, err)
+		assert.Equal(t, aggregate, parallelAggregate)
+		_, err = AggregateRangeParallel(commits, seed, 3, n, config)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		parallelFolded, err := AggregatePolynomialsParallel(polys, seed, 3, 30, config)
+		require.NoError(t, err)
+		assert.Equal(t, folded, parallelFolded)
+		_, err = AggregatePolynomialsParallel(polys, seed, 30, 3, config)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		parallelProof, err := sdk.OpenRangeFromParallel(polys[3:31], point, seed, 3, config)
+		require.NoError(t, err)
+		assert.Equal(t, proof, parallelProof)
+		_, err = sdk.OpenRangeFromParallel(nil, point, seed, 3, config)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		// batches added in parallel and blobs added one at a time fold the same polynomial
+		folder := sdk.NewFolder(seed)
+		require.NoError(t, folder.AddParallel(3, polys[3:20], config))
+		for i := uint64(20); i < 25; i++ {
+			require.NoError(t, folder.Add(i, polys[i]))
+		}
+		require.NoError(t, folder.AddParallel(25, polys[25:31], config))
+		assert.Equal(t, 28, folder.Len())
+		assert.Equal(t, folded, folder.Polynomial())
+		assert.ErrorIs(t, folder.AddParallel(31, [][]fr.Element{polys[31], randomPolynomial(65)}, config), ErrPolynomialTooLarge)
+		assert.Equal(t, folded, folder.Polynomial())
+	}
+}
+
+// benchmarkBlobs returns n polynomials of random 4 KiB blobs and an srs fitting them.
+func benchmarkBlobs(b *testing.B, n int) (*DomiconSdk, [][]fr.Element) {
+	polys := make([][]fr.Element, n)
+	for i := range polys {
+		data := make([]byte, 4*1024)
+		_, err := rand.Read(data)
+		require.NoError(b, err)
+		polys[i] = DataToPolynomial(data)
+	}
+	srs, err := kzg.NewSRS(uint64(len(polys[0])), big.NewInt(42))
+	require.NoError(b, err)
+	return NewDomiconSdk(srs), polys
+}
+
+func BenchmarkGetRandomsHash(b *testing.B) {
+	var gamma fr.Element
+	gamma.SetRandom()
+	const n = 1 << 14
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = GetRandomsHash(gamma, 0, n)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = GetRandomsHashParallel(gamma, 0, n, ParallelConfig{})
+		}
+	})
+}
+
+func BenchmarkFoldedPolynomials(b *testing.B) {
+	_, polys := benchmarkBlobs(b, 1024)
+	var gamma fr.Element
+	gamma.SetRandom()
+	b.ResetTimer()
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = FoldedPolynomials(polys, gamma)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = FoldedPolynomialsParallel(polys, gamma, ParallelConfig{})
+		}
+	})
+}
+
+func BenchmarkCommitMany(b *testing.B) {
+	sdk, polys := benchmarkBlobs(b, 256)
+	b.ResetTimer()
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, poly := range polys {
+				_, _ = kzg.Commit(poly, sdk.SRS().Pk)
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = sdk.CommitMany(polys, ParallelConfig{})
+		}
+	})
+}
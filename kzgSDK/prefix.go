@@ -0,0 +1,279 @@
+package kzgsdk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrPrefixCacheMismatch is returned when a saved prefix cache does not match its challenge or commitments.
+var ErrPrefixCacheMismatch = errors.New("prefix aggregate cache mismatch")
+
+// prefixHeaderLen is the length of the fixed part of an encoded PrefixAggregates:
+// seed, start, end, interval, digest of the commitments and number of checkpoints.
+const prefixHeaderLen = len(FoldSeed{}) + 3*8 + common.HashLength + 8
+
+// PrefixAggregates answers CM_{start,j} (see AggregateRange) for any j of a challenge over
+// [start, end], the aggregates a storage node submits at each step of the bisection.
+//
+// The cache keeps one checkpoint every interval indices, checkpoint k being CM_{start,start+(k+1)·interval-1}.
+// A query adds to the closest checkpoint the multi-exponentiation of less than interval commitments,
+// with the default interval of √n both the cache and the queries are O(√n) points.
+type PrefixAggregates struct {
+	seed     FoldSeed
+	start    uint64
+	end      uint64
+	interval uint64
+	// commits are the commitments of [start, end], commits[k] being the one at start+k
+	commits     []kzg.Digest
+	checkpoints []kzg.Digest
+}
+
+// NewPrefixAggregates builds the prefix cache of a challenge over [start, end] with seed,
+// commits[i] being the commitment at namespace index i. An interval of 0 selects √(end-start+1).
+func NewPrefixAggregates(commits []kzg.Digest, seed FoldSeed, start uint64, end uint64, interval uint64) (*PrefixAggregates, error) {
+	if err := checkRange(start, end, len(commits)); err != nil {
+		return nil, err
+	}
+	return NewPrefixAggregatesFrom(commits[start:end+1], seed, start, interval)
+}
+
+// NewPrefixAggregatesFrom is NewPrefixAggregates for the commitments of the range only,
+// commits[k] being the commitment at namespace index start+k, so the range is
+// [start, start+len(commits)-1].
+func NewPrefixAggregatesFrom(commits []kzg.Digest, seed FoldSeed, start uint64, interval uint64) (*PrefixAggregates, error) {
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("%w: no commitment from %d", ErrInvalidRange, start)
+	}
+	n := uint64(len(commits))
+	if interval == 0 {
+		interval = max(1, uint64(math.Sqrt(float64(n))))
+	}
+	p := &PrefixAggregates{
+		seed:        seed,
+		start:       start,
+		end:         start + n - 1,
+		interval:    interval,
+		commits:     commits,
+		checkpoints: make([]kzg.Digest, 0, n/interval),
+	}
+	var prefix kzg.Digest
+	for k := uint64(0); n-k >= interval; k += interval {
+		var chunk kzg.Digest
+		if _, err := chunk.MultiExp(commits[k:k+interval], seed.Coefficients(start+k, start+k+interval-1), ecc.MultiExpConfig{}); err != nil {
+			return nil, err
+		}
+		prefix.Add(&prefix, &chunk)
+		p.checkpoints = append(p.checkpoints, prefix)
+	}
+	return p, nil
+}
+
+// Seed returns the seed of the challenge.
+func (p *PrefixAggregates) Seed() FoldSeed {
+	return p.seed
+}
+
+// Start returns the first index of the challenge range.
+func (p *PrefixAggregates) Start() uint64 {
+	return p.start
+}
+
+// End returns the last index of the challenge range.
+func (p *PrefixAggregates) End() uint64 {
+	return p.end
+}
+
+// Aggregate returns CM_{start,j}.
+func (p *PrefixAggregates) Aggregate(j uint64) (kzg.Digest, error) {
+	if j < p.start || j > p.end {
+		return kzg.Digest{}, fmt.Errorf("%w: %d outside of [%d, %d]", ErrInvalidRange, j, p.start, p.end)
+	}
+	full := (j - p.start + 1) / p.interval
+	var aggregate kzg.Digest
+	if full > 0 {
+		aggregate = p.checkpoints[full-1]
+	}
+	from := p.start + full*p.interval
+	if from > j {
+		return aggregate, nil
+	}
+	var rest kzg.Digest
+	if _, err := rest.MultiExp(p.commits[from-p.start:j-p.start+1], p.seed.Coefficients(from, j), ecc.MultiExpConfig{}); err != nil {
+		return kzg.Digest{}, err
+	}
+	aggregate.Add(&aggregate, &rest)
+	return aggregate, nil
+}
+
+// commitsDigest is the keccak256 digest of the compressed commitments.
+func commitsDigest(commits []kzg.Digest) common.Hash {
+	hasher := crypto.NewKeccakState()
+	for i := range commits {
+		b := commits[i].Bytes()
+		hasher.Write(b[:])
+	}
+	var digest common.Hash
+	hasher.Read(digest[:])
+	return digest
+}
+
+// WritePrefixAggregates writes the checkpoints of p, along with the challenge and the digest of the
+// commitments they were computed from, followed by the keccak256 digest of the encoding.
+func WritePrefixAggregates(w io.Writer, p *PrefixAggregates) error {
+	header := make([]byte, 0, prefixHeaderLen)
+	header = append(header, p.seed[:]...)
+	header = binary.BigEndian.AppendUint64(header, p.start)
+	header = binary.BigEndian.AppendUint64(header, p.end)
+	header = binary.BigEndian.AppendUint64(header, p.interval)
+	header = append(header, commitsDigest(p.commits).Bytes()...)
+	header = binary.BigEndian.AppendUint64(header, uint64(len(p.checkpoints)))
+
+	hasher := crypto.NewKeccakState()
+	mw := io.MultiWriter(w, hasher)
+	if _, err := mw.Write(header); err != nil {
+		return err
+	}
+	for i := range p.checkpoints {
+		b := p.checkpoints[i].RawBytes()
+		if _, err := mw.Write(b[:]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(hasher.Sum(nil))
+	return err
+}
+
+// ReadPrefixAggregates decodes a cache written by WritePrefixAggregates for the given commitments.
+// It returns ErrPrefixCacheMismatch if the file is corrupted or was computed from other commitments.
+func ReadPrefixAggregates(r io.Reader, commits []kzg.Digest) (*PrefixAggregates, error) {
+	return readPrefixAggregates(r, func(start uint64, end uint64) ([]kzg.Digest, error) {
+		if err := checkRange(start, end, len(commits)); err != nil {
+			return nil, err
+		}
+		return commits[start : end+1], nil
+	})
+}
+
+// ReadPrefixAggregatesFrom is ReadPrefixAggregates for the commitments of the range only,
+// commits[k] being the commitment at namespace index start+k as for NewPrefixAggregatesFrom.
+// A cache saved for another range is a mismatch.
+func ReadPrefixAggregatesFrom(r io.Reader, commits []kzg.Digest, start uint64) (*PrefixAggregates, error) {
+	return readPrefixAggregates(r, func(savedStart uint64, savedEnd uint64) ([]kzg.Digest, error) {
+		if savedStart != start || savedEnd-savedStart+1 != uint64(len(commits)) {
+			return nil, fmt.Errorf("saved for [%d, %d], not for %d commitments from %d", savedStart, savedEnd, len(commits), start)
+		}
+		return commits, nil
+	})
+}
+
+// readPrefixAggregates decodes a cache, rangeCommits returning the commitments of the saved range.
+func readPrefixAggregates(r io.Reader, rangeCommits func(start uint64, end uint64) ([]kzg.Digest, error)) (*PrefixAggregates, error) {
+	hasher := crypto.NewKeccakState()
+	tee := io.TeeReader(r, hasher)
+	header := make([]byte, prefixHeaderLen)
+	if _, err := io.ReadFull(tee, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPrefixCacheMismatch, err)
+	}
+	p := &PrefixAggregates{}
+	header = header[copy(p.seed[:], header):]
+	p.start = binary.BigEndian.Uint64(header[0:])
+	p.end = binary.BigEndian.Uint64(header[8:])
+	p.interval = binary.BigEndian.Uint64(header[16:])
+	digest := common.BytesToHash(header[24 : 24+common.HashLength])
+	count := binary.BigEndian.Uint64(header[24+common.HashLength:])
+
+	if p.start > p.end {
+		return nil, fmt.Errorf("%w: range [%d, %d]", ErrPrefixCacheMismatch, p.start, p.end)
+	}
+	commits, err := rangeCommits(p.start, p.end)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPrefixCacheMismatch, err)
+	}
+	if p.interval == 0 || count != (p.end-p.start+1)/p.interval {
+		return nil, fmt.Errorf("%w: %d checkpoints every %d indices", ErrPrefixCacheMismatch, count, p.interval)
+	}
+	p.commits = commits
+	if digest != commitsDigest(p.commits) {
+		return nil, fmt.Errorf("%w: computed from other commitments", ErrPrefixCacheMismatch)
+	}
+
+	p.checkpoints = make([]kzg.Digest, count)
+	buf := make([]byte, bn254.SizeOfG1AffineUncompressed)
+	for i := range p.checkpoints {
+		if _, err := io.ReadFull(tee, buf); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPrefixCacheMismatch, err)
+		}
+		if _, err := p.checkpoints[i].SetBytes(buf); err != nil {
+			return nil, fmt.Errorf("%w: checkpoint %d: %v", ErrPrefixCacheMismatch, i, err)
+		}
+	}
+
+	var recorded common.Hash
+	if _, err := io.ReadFull(r, recorded[:]); err != nil {
+		return nil, fmt.Errorf("%w: missing digest: %v", ErrPrefixCacheMismatch, err)
+	}
+	if !bytes.Equal(recorded[:], hasher.Sum(nil)) {
+		return nil, fmt.Errorf("%w: digest", ErrPrefixCacheMismatch)
+	}
+	return p, nil
+}
+
+// SavePrefixAggregates atomically writes p to path, so a node restarted in the middle of a
+// challenge finds either the previous cache or the new one.
+func SavePrefixAggregates(path string, p *PrefixAggregates) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	w := bufio.NewWriter(file)
+	if err := WritePrefixAggregates(w, p); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// LoadPrefixAggregates reads a cache saved by SavePrefixAggregates, see ReadPrefixAggregates.
+func LoadPrefixAggregates(path string, commits []kzg.Digest) (*PrefixAggregates, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadPrefixAggregates(bufio.NewReader(file), commits)
+}
+
+// LoadPrefixAggregatesFrom reads a cache saved by SavePrefixAggregates, see ReadPrefixAggregatesFrom.
+func LoadPrefixAggregatesFrom(path string, commits []kzg.Digest, start uint64) (*PrefixAggregates, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadPrefixAggregatesFrom(bufio.NewReader(file), commits, start)
+}
@@ -0,0 +1,149 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomCommitments(n int) []kzg.Digest {
+	commits := make([]kzg.Digest, n)
+	for i := range commits {
+		var s fr.Element
+		s.SetRandom()
+		commits[i].ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	}
+	return commits
+}
+
+func TestPrefixAggregates(t *testing.T) {
+	commits := randomCommitments(120)
+	seed := randomSeed()
+
+	for _, tt := range []struct {
+		start, end, interval uint64
+	}{
+		{0, 119, 0},
+		{5, 100, 0},
+		{17, 17, 0},
+		{10, 60, 1},
+		{10, 60, 7},
+		{10, 60, 51},
+		{10, 60, 100},
+	} {
+		p, err := NewPrefixAggregates(commits, seed, tt.start, tt.end, tt.interval)
+		assert.NoError(t, err)
+		for j := tt.start; j <= tt.end; j++ {
+			expected, err := AggregateRange(commits, seed, tt.start, j)
+			assert.NoError(t, err)
+			aggregate, err := p.Aggregate(j)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, aggregate, "[%d, %d] interval %d", tt.start, j, tt.interval)
+		}
+		_, err = p.Aggregate(tt.end + 1)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+		if tt.start > 0 {
+			_, err = p.Aggregate(tt.start - 1)
+			assert.ErrorIs(t, err, ErrInvalidRange)
+		}
+
+		// the same cache from the commitments of the range only
+		from, err := NewPrefixAggregatesFrom(commits[tt.start:tt.end+1], seed, tt.start, tt.interval)
+		assert.NoError(t, err)
+		assert.Equal(t, p, from)
+	}
+
+	_, err := NewPrefixAggregates(commits, seed, 10, 120, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = NewPrefixAggregatesFrom(nil, seed, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	// a range far in the namespace only takes the memory of its commitments
+	far, err := NewPrefixAggregatesFrom(commits[:4], seed, 80000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(80003), far.End())
+	var expected kzg.Digest
+	for k := uint64(0); k < 3; k++ {
+		expected = AggregateStep(expected, commits[k], seed, 80000+k)
+	}
+	aggregate, err := far.Aggregate(80002)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, aggregate)
+}
+
+func TestPrefixAggregatesPersistence(t *testing.T) {
+	commits := randomCommitments(50)
+	seed := randomSeed()
+	p, err := NewPrefixAggregates(commits, seed, 3, 47, 0)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "challenge-7")
+	assert.NoError(t, SavePrefixAggregates(path, p))
+	loaded, err := LoadPrefixAggregates(path, commits)
+	assert.NoError(t, err)
+	assert.Equal(t, p, loaded)
+	assert.Equal(t, uint64(3), loaded.Start())
+	assert.Equal(t, uint64(47), loaded.End())
+	expected, err := AggregateRange(commits, seed, 3, 30)
+	assert.NoError(t, err)
+	aggregate, err := loaded.Aggregate(30)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, aggregate)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WritePrefixAggregates(&buf, p))
+	encoded := buf.Bytes()
+
+	// other commitments
+	other := append([]kzg.Digest{}, commits...)
+	other[20] = commits[21]
+	_, err = ReadPrefixAggregates(bytes.NewReader(encoded), other)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	// too few commitments for the saved range
+	_, err = ReadPrefixAggregates(bytes.NewReader(encoded), commits[:40])
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	// corrupted checkpoint
+	corrupted := append([]byte{}, encoded...)
+	corrupted[prefixHeaderLen+5] ^= 1
+	_, err = ReadPrefixAggregates(bytes.NewReader(corrupted), commits)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	// truncated file
+	_, err = ReadPrefixAggregates(bytes.NewReader(encoded[:len(encoded)-1]), commits)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+
+	// the commitments of the range only
+	loaded, err = LoadPrefixAggregatesFrom(path, commits[3:48], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, p, loaded)
+	assert.Equal(t, seed, loaded.Seed())
+	_, err = ReadPrefixAggregatesFrom(bytes.NewReader(encoded), other[3:48], 3)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	_, err = ReadPrefixAggregatesFrom(bytes.NewReader(encoded), commits[3:47], 3)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	_, err = ReadPrefixAggregatesFrom(bytes.NewReader(encoded), commits[4:49], 4)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+	_, err = ReadPrefixAggregatesFrom(bytes.NewReader(corrupted), commits[3:48], 3)
+	assert.ErrorIs(t, err, ErrPrefixCacheMismatch)
+}
+
+func BenchmarkPrefixAggregates(b *testing.B) {
+	commits := randomCommitments(1 << 14)
+	seed := randomSeed()
+	p, err := NewPrefixAggregates(commits, seed, 0, uint64(len(commits)-1), 0)
+	assert.NoError(b, err)
+	b.Run("prefix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = p.Aggregate(uint64(len(commits)/2 + i%100))
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = AggregateRange(commits, seed, 0, uint64(len(commits)/2+i%100))
+		}
+	})
+}
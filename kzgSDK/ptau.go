@@ -0,0 +1,230 @@
+package kzgsdk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// Layout of the snarkjs powers-of-tau files (.ptau).
+// Points are stored uncompressed, each coordinate as a little endian integer in Montgomery form.
+const (
+	ptauMagic         = "ptau"
+	ptauSectionHeader = 1
+	ptauSectionTauG1  = 2
+	ptauSectionTauG2  = 3
+	ptauFieldSize     = 32
+	ptauG1Size        = 2 * ptauFieldSize
+	ptauG2Size        = 4 * ptauFieldSize
+)
+
+var (
+	// ErrPtauFormat is returned when a file is not a valid BN254 powers-of-tau transcript.
+	ErrPtauFormat = errors.New("invalid ptau file")
+	// ErrPtauSize is returned when the requested srs size is not a power of two or exceeds the transcript.
+	ErrPtauSize = errors.New("invalid srs size for ptau file")
+)
+
+// ptauSection locates a section in a ptau file.
+type ptauSection struct {
+	offset int64
+	size   int64
+}
+
+// ImportPtau reads a snarkjs powers-of-tau file from disk and builds a kzg.SRS of the given size,
+// see ImportPtauFromReader.
+func ImportPtau(path string, size uint64) (*kzg.SRS, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ImportPtauFromReader(file, size)
+}
+
+// ImportPtauFromReader builds a kzg.SRS from the tauG1 and tauG2 sections of a snarkjs
+// powers-of-tau transcript. size is the number of G1 powers, it must be a power of two
+// no larger than 2^power of the transcript. The resulting srs is checked with ValidateSRS.
+func ImportPtauFromReader(r io.ReadSeeker, size uint64) (*kzg.SRS, error) {
+	sections, err := readPtauSections(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range []uint32{ptauSectionHeader, ptauSectionTauG1, ptauSectionTauG2} {
+		if _, ok := sections[id]; !ok {
+			return nil, fmt.Errorf("%w: missing section %d", ErrPtauFormat, id)
+		}
+	}
+
+	power, err := readPtauHeader(r, sections[ptauSectionHeader])
+	if err != nil {
+		return nil, err
+	}
+	if size < 2 || size != ecc.NextPowerOfTwo(size) || power >= 64 || size > uint64(1)<<power {
+		return nil, fmt.Errorf("%w: %d, transcript power %d", ErrPtauSize, size, power)
+	}
+	tauG1 := sections[ptauSectionTauG1]
+	if tauG1.size < int64(size)*ptauG1Size {
+		return nil, fmt.Errorf("%w: tauG1 section holds %d points", ErrPtauFormat, tauG1.size/ptauG1Size)
+	}
+	tauG2 := sections[ptauSectionTauG2]
+	if tauG2.size < 2*ptauG2Size {
+		return nil, fmt.Errorf("%w: tauG2 section holds %d points", ErrPtauFormat, tauG2.size/ptauG2Size)
+	}
+
+	srs := &kzg.SRS{Pk: kzg.ProvingKey{G1: make([]bn254.G1Affine, size)}}
+	if _, err = r.Seek(tauG1.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+	for i := range srs.Pk.G1 {
+		if err = readPtauG1(br, &srs.Pk.G1[i]); err != nil {
+			return nil, fmt.Errorf("tauG1[%d]: %w", i, err)
+		}
+	}
+	if _, err = r.Seek(tauG2.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	br.Reset(r)
+	for i := range srs.Vk.G2 {
+		if err = readPtauG2(br, &srs.Vk.G2[i]); err != nil {
+			return nil, fmt.Errorf("tauG2[%d]: %w", i, err)
+		}
+	}
+	srs.Vk.G1 = srs.Pk.G1[0]
+
+	if err = ValidateSRS(srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
+
+// readPtauSections reads the file header and returns the position of every section.
+func readPtauSections(r io.ReadSeeker) (map[uint32]ptauSection, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var header struct {
+		Magic     [4]byte
+		Version   uint32
+		NSections uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPtauFormat, err)
+	}
+	if string(header.Magic[:]) != ptauMagic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrPtauFormat, header.Magic[:])
+	}
+
+	sections := make(map[uint32]ptauSection, header.NSections)
+	for i := uint32(0); i < header.NSections; i++ {
+		var section struct {
+			Type uint32
+			Size uint64
+		}
+		if err := binary.Read(r, binary.LittleEndian, &section); err != nil {
+			return nil, fmt.Errorf("%w: section %d: %v", ErrPtauFormat, i, err)
+		}
+		if _, ok := sections[section.Type]; ok {
+			return nil, fmt.Errorf("%w: duplicated section %d", ErrPtauFormat, section.Type)
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if section.Size > uint64(end-offset) {
+			return nil, fmt.Errorf("%w: section %d is truncated", ErrPtauFormat, section.Type)
+		}
+		sections[section.Type] = ptauSection{offset: offset, size: int64(section.Size)}
+		if _, err = r.Seek(int64(section.Size), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+	return sections, nil
+}
+
+// readPtauHeader checks the transcript is over the BN254 base field and returns its power.
+func readPtauHeader(r io.ReadSeeker, section ptauSection) (uint32, error) {
+	if _, err := r.Seek(section.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return 0, fmt.Errorf("%w: header: %v", ErrPtauFormat, err)
+	}
+	if n8 != ptauFieldSize {
+		return 0, fmt.Errorf("%w: field size %d is not BN254", ErrPtauFormat, n8)
+	}
+	q := make([]byte, n8)
+	if _, err := io.ReadFull(r, q); err != nil {
+		return 0, fmt.Errorf("%w: header: %v", ErrPtauFormat, err)
+	}
+	if leToBigInt(q).Cmp(fp.Modulus()) != 0 {
+		return 0, fmt.Errorf("%w: field modulus is not BN254", ErrPtauFormat)
+	}
+	var power uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return 0, fmt.Errorf("%w: header: %v", ErrPtauFormat, err)
+	}
+	return power, nil
+}
+
+func readPtauG1(r io.Reader, p *bn254.G1Affine) error {
+	if err := readPtauFp(r, &p.X); err != nil {
+		return err
+	}
+	return readPtauFp(r, &p.Y)
+}
+
+func readPtauG2(r io.Reader, p *bn254.G2Affine) error {
+	for _, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err := readPtauFp(r, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ptauMontgomeryInv is R⁻¹ mod q with R = 2²⁵⁶, used to leave the Montgomery form of ptau coordinates.
+var ptauMontgomeryInv = func() fp.Element {
+	var rInv fp.Element
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	rInv.SetBigInt(r.ModInverse(r, fp.Modulus()))
+	return rInv
+}()
+
+// readPtauFp reads a little endian Montgomery encoded coordinate.
+func readPtauFp(r io.Reader, e *fp.Element) error {
+	var buf [ptauFieldSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrPtauFormat, err)
+	}
+	v := leToBigInt(buf[:])
+	if v.Cmp(fp.Modulus()) >= 0 {
+		return fmt.Errorf("%w: coordinate out of range", ErrPtauFormat)
+	}
+	e.SetBigInt(v)
+	e.Mul(e, &ptauMontgomeryInv)
+	return nil
+}
+
+func leToBigInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
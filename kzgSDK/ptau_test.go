@@ -0,0 +1,168 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ptauTestPower = 5
+
+// writeTestPtau encodes srs the way snarkjs lays out a powers-of-tau transcript of the given power.
+func writeTestPtau(t *testing.T, srs *kzg.SRS, power uint32) []byte {
+	putFp := func(buf *bytes.Buffer, e fp.Element) {
+		var r fp.Element
+		r.SetBigInt(new(big.Int).Lsh(big.NewInt(1), 256))
+		e.Mul(&e, &r)
+		be := e.Bytes()
+		for i := len(be) - 1; i >= 0; i-- {
+			buf.WriteByte(be[i])
+		}
+	}
+	var header, tauG1, tauG2, alpha bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, uint32(ptauFieldSize))
+	q := fp.Modulus().FillBytes(make([]byte, ptauFieldSize))
+	for i := len(q) - 1; i >= 0; i-- {
+		header.WriteByte(q[i])
+	}
+	_ = binary.Write(&header, binary.LittleEndian, power)
+	_ = binary.Write(&header, binary.LittleEndian, uint32(28))
+
+	nG1 := 2*(1<<power) - 1
+	assert.LessOrEqual(t, nG1, len(srs.Pk.G1))
+	for i := 0; i < nG1; i++ {
+		putFp(&tauG1, srs.Pk.G1[i].X)
+		putFp(&tauG1, srs.Pk.G1[i].Y)
+	}
+	for i := 0; i < 1<<power; i++ {
+		g2 := srs.Vk.G2[min(i, 1)]
+		for _, e := range []fp.Element{g2.X.A0, g2.X.A1, g2.Y.A0, g2.Y.A1} {
+			putFp(&tauG2, e)
+		}
+	}
+	alpha.Write(make([]byte, ptauG1Size))
+
+	var out bytes.Buffer
+	out.WriteString(ptauMagic)
+	_ = binary.Write(&out, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&out, binary.LittleEndian, uint32(4))
+	// sections do not have to be sorted
+	for _, section := range []struct {
+		id   uint32
+		data []byte
+	}{
+		{ptauSectionHeader, header.Bytes()},
+		{ptauSectionTauG2, tauG2.Bytes()},
+		{4, alpha.Bytes()},
+		{ptauSectionTauG1, tauG1.Bytes()},
+	} {
+		_ = binary.Write(&out, binary.LittleEndian, section.id)
+		_ = binary.Write(&out, binary.LittleEndian, uint64(len(section.data)))
+		out.Write(section.data)
+	}
+	return out.Bytes()
+}
+
+func TestImportPtau(t *testing.T) {
+	generated, err := kzg.NewSRS(1<<(ptauTestPower+1), big.NewInt(42))
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "test.ptau")
+	assert.NoError(t, os.WriteFile(path, writeTestPtau(t, generated, ptauTestPower), 0o600))
+
+	for _, size := range []uint64{2, 16, 1 << ptauTestPower} {
+		srs, err := ImportPtau(path, size)
+		assert.NoError(t, err)
+		assert.Equal(t, generated.Pk.G1[:size], srs.Pk.G1)
+		assert.Equal(t, generated.Vk, srs.Vk)
+	}
+
+	srs, err := ImportPtau(path, 1<<ptauTestPower)
+	assert.NoError(t, err)
+	poly := randomPolynomial(1 << ptauTestPower)
+	commit, err := kzg.Commit(poly, srs.Pk)
+	assert.NoError(t, err)
+	var open fr.Element
+	open.SetRandom()
+	proof, err := kzg.Open(poly, open, srs.Pk)
+	assert.NoError(t, err)
+	assert.NoError(t, kzg.Verify(&commit, &proof, open, srs.Vk))
+}
+
+func TestImportPtauErrors(t *testing.T) {
+	generated, err := kzg.NewSRS(1<<(ptauTestPower+1), big.NewInt(42))
+	assert.NoError(t, err)
+	encoded := writeTestPtau(t, generated, ptauTestPower)
+
+	for _, size := range []uint64{0, 1, 3, 24, 1 << (ptauTestPower + 1)} {
+		_, err = ImportPtauFromReader(bytes.NewReader(encoded), size)
+		assert.ErrorIs(t, err, ErrPtauSize, "size %d", size)
+	}
+
+	badMagic := bytes.Clone(encoded)
+	copy(badMagic, "zkey")
+	_, err = ImportPtauFromReader(bytes.NewReader(badMagic), 16)
+	assert.ErrorIs(t, err, ErrPtauFormat)
+
+	_, err = ImportPtauFromReader(bytes.NewReader(encoded[:len(encoded)-1]), 16)
+	assert.ErrorIs(t, err, ErrPtauFormat)
+
+	// transcript over another field
+	otherField := bytes.Clone(encoded)
+	otherField[4+4+4+4+8+4] ^= 1
+	_, err = ImportPtauFromReader(bytes.NewReader(otherField), 16)
+	assert.ErrorIs(t, err, ErrPtauFormat)
+
+	// a tauG1 power that does not match tauG2
+	generated.Pk.G1[7] = generated.Pk.G1[6]
+	_, err = ImportPtauFromReader(bytes.NewReader(writeTestPtau(t, generated, ptauTestPower)), 16)
+	var srsErr *SRSError
+	assert.ErrorAs(t, err, &srsErr)
+	assert.ErrorIs(t, err, ErrSRSInconsistent)
+	assert.Equal(t, 7, srsErr.Index)
+}
+
+// hermezTauG2 is τ·G2 of the Hermez powers-of-tau ceremony, the X2 point of the PLONK verifiers
+// snarkjs generates from its transcripts (X2x1, X2x2, X2y1, X2y2 being X.A0, X.A1, Y.A0, Y.A1).
+var hermezTauG2 = [4]string{
+	"21831381940315734285607113342023901060522397560371972897001948545212302161822",
+	"17231025384763736816414546592865244497437017442647097510447326538965263639101",
+	"2388026358213174446665280700919698872609886601280537296205114254867301080648",
+	"11507326595632554467052522095592665270651932854513688777769618397986436103170",
+}
+
+// TestImportHermezPtau imports a published transcript, powersOfTau28_hez_final_XX.ptau of the
+// Hermez ceremony, when one is put in testdata. The files are too large to be part of the repository.
+func TestImportHermezPtau(t *testing.T) {
+	var tauG2 bn254.G2Affine
+	for i, e := range []*fp.Element{&tauG2.X.A0, &tauG2.X.A1, &tauG2.Y.A0, &tauG2.Y.A1} {
+		_, err := e.SetString(hermezTauG2[i])
+		require.NoError(t, err)
+	}
+	require.True(t, tauG2.IsOnCurve())
+	require.True(t, tauG2.IsInSubGroup())
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "powersOfTau28_hez_final_*.ptau"))
+	require.NoError(t, err)
+	if len(paths) == 0 {
+		t.Skip("no testdata/powersOfTau28_hez_final_*.ptau, the files are listed in the snarkjs README")
+	}
+	for _, path := range paths {
+		srs, err := ImportPtau(path, 16)
+		require.NoError(t, err, path)
+		_, _, g1, g2 := bn254.Generators()
+		assert.Equal(t, g1, srs.Pk.G1[0], path)
+		assert.Equal(t, g2, srs.Vk.G2[0], path)
+		assert.Equal(t, tauG2, srs.Vk.G2[1], path)
+		assert.NoError(t, ValidateSRS(srs), path)
+	}
+}
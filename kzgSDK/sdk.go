@@ -1,6 +1,10 @@
-package main
+package kzgsdk
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
@@ -8,24 +12,96 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-const dChunkSize = 30
+var (
+	// ErrPolynomialTooLarge is returned when a (folded) polynomial has more coefficients than the srs.
+	ErrPolynomialTooLarge = errors.New("polynomial is larger than the srs")
+	// ErrEmptyInput is returned when there is no polynomial or data to fold.
+	ErrEmptyInput = errors.New("empty input")
+	// ErrInvalidChallengeR is returned when the challenge r is not a uint256.
+	ErrInvalidChallengeR = errors.New("challenge r is not a uint256")
+	// ErrPointOutOfField is returned when the challenge point is not a canonical scalar field element.
+	ErrPointOutOfField = errors.New("challenge point is not in the scalar field")
+)
 
+// DomiconSdk holds the KZG setup parameters shared by storage nodes and challengers.
 type DomiconSdk struct {
 	srs *kzg.SRS
 }
 
+// NewDomiconSdk creates a DomiconSdk instance backed by the given SRS.
+func NewDomiconSdk(srs *kzg.SRS) *DomiconSdk {
+	return &DomiconSdk{srs: srs}
+}
+
+// NewDomiconSdkFromSol creates a DomiconSdk instance backed by the SRS of the Solidity verifier.
+func NewDomiconSdkFromSol() (*DomiconSdk, error) {
+	srs, err := SRSFromSol()
+	if err != nil {
+		return nil, err
+	}
+	return NewDomiconSdk(srs), nil
+}
+
+// NewDomiconSdkFromFile creates a DomiconSdk instance backed by a cached srs file, see LoadSRS.
+func NewDomiconSdkFromFile(path string) (*DomiconSdk, error) {
+	srs, err := LoadSRS(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewDomiconSdk(srs), nil
+}
+
+// SRS returns the setup parameters used by the sdk.
+func (sdk *DomiconSdk) SRS() *kzg.SRS {
+	return sdk.srs
+}
+
+// Commit computes the KZG commitment of a polynomial.
+func (sdk *DomiconSdk) Commit(polynomial []fr.Element) (kzg.Digest, error) {
+	return kzg.Commit(polynomial, sdk.srs.Pk)
+}
+
+// CommitData encodes data with DataToPolynomial and computes its KZG commitment.
+func (sdk *DomiconSdk) CommitData(data []byte) (kzg.Digest, error) {
+	return kzg.Commit(DataToPolynomial(data), sdk.srs.Pk)
+}
+
+// Fold computes the aggregated commitment of Commits[from:to] using gamma, see FoldedCommits.
+func (sdk *DomiconSdk) Fold(Commits []kzg.Digest, gamma fr.Element, from uint, to uint) (kzg.Digest, error) {
+	return FoldedCommits(Commits, gamma, from, to)
+}
+
+// Open folds the polynomials using gamma and computes the opening proof of the folded polynomial at openPoint.
+func (sdk *DomiconSdk) Open(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	gamma fr.Element,
+) (kzg.OpeningProof, error) {
+	return TryResponce(polynomials, openPoint, gamma, sdk.srs)
+}
+
+// Verify checks the opening proof of a (folded) commitment at openPoint.
+func (sdk *DomiconSdk) Verify(commit *kzg.Digest, proof *kzg.OpeningProof, openPoint fr.Element) error {
+	return kzg.Verify(commit, proof, openPoint, sdk.srs.Vk)
+}
+
 // FoldedCommits computes a folded commitment from a slice of commitments using a gamma element.
+// Commits[i] is folded with the coefficient of index i, so the result is CM_{from,to-1} of
+// AggregateRange and the range must be non-empty.
 func FoldedCommits(
 	Commits []kzg.Digest,
 	gamma fr.Element,
 	from uint,
 	to uint,
 ) (kzg.Digest, error) {
+	if from >= to || to > uint(len(Commits)) {
+		return kzg.Digest{}, fmt.Errorf("%w: [%d, %d) of %d commitments", ErrInvalidRange, from, to, len(Commits))
+	}
 	var AggreCommit kzg.Digest
 	//Generate random hashes based on gamma, from, and to indices
 	gammasBytes := GetRandomsHash(gamma, from, to)
 	gammas := HashToFrElements(gammasBytes)
-	_, err := AggreCommit.MultiExp(Commits[from:to], gammas[from:to], ecc.MultiExpConfig{})
+	_, err := AggreCommit.MultiExp(Commits[from:to], gammas, ecc.MultiExpConfig{})
 	return AggreCommit, err
 }
 
@@ -37,29 +113,6 @@ func HashToFrElements(gammasHash []common.Hash) []fr.Element {
 	return gammas
 }
 
-func chunkBytes(data []byte, chunkSize int) [][]byte {
-	var chunks [][]byte
-	for i := 0; i < len(data); i += chunkSize {
-		end := i + chunkSize
-		if end > len(data) {
-			end = len(data)
-		}
-		chunks = append(chunks, data[i:end])
-	}
-	return chunks
-}
-
-// dataToPolynomial converts byte data into a slice of fr.Element representing a polynomial.
-func dataToPolynomial(data []byte) []fr.Element {
-	chunks := chunkBytes(data, dChunkSize)
-	chunksLen := len(chunks)
-	ps := make([]fr.Element, chunksLen)
-	for i, chunk := range chunks {
-		ps[i].SetBytes(chunk)
-	}
-	return ps
-}
-
 // FoldedPolynomials computes a folded polynomial from a slice of polynomials using a gamma element.
 func FoldedPolynomials(
 	polynomials [][]fr.Element,
@@ -88,6 +141,7 @@ func FoldedPolynomials(
 }
 
 // Responce generates an opening proof of polynomials using the KZG commitment scheme.
+// It panics if the proof cannot be computed, see TryResponce for the error-returning version.
 //
 //	polynomials: Coefficients of polynomials, represented as a 2D array of fr.Element.
 //	openPoint: Point at which the polynomial is opened for verification.
@@ -99,17 +153,33 @@ func Responce(
 	gamma fr.Element,
 	srs *kzg.SRS,
 ) kzg.OpeningProof {
-	//Transform the array of polynomials into a single polynomial using gamma.
-	FoldPoly := FoldedPolynomials(polynomials, gamma)
-	//Compute the proof of the polynomial FoldPoly  at the opening point.
-	proof, err := kzg.Open(FoldPoly, openPoint, srs.Pk)
+	proof, err := TryResponce(polynomials, openPoint, gamma, srs)
 	if err != nil {
 		panic(err)
 	}
 	return proof
 }
 
+// TryResponce generates an opening proof of polynomials using the KZG commitment scheme.
+// It returns ErrEmptyInput if there is nothing to fold and ErrPolynomialTooLarge if the
+// folded polynomial does not fit in the srs.
+func TryResponce(
+	polynomials [][]fr.Element,
+	openPoint fr.Element,
+	gamma fr.Element,
+	srs *kzg.SRS,
+) (kzg.OpeningProof, error) {
+	if err := checkPolynomials(polynomials, srs); err != nil {
+		return kzg.OpeningProof{}, err
+	}
+	//Transform the array of polynomials into a single polynomial using gamma.
+	FoldPoly := FoldedPolynomials(polynomials, gamma)
+	//Compute the proof of the polynomial FoldPoly  at the opening point.
+	return kzg.Open(FoldPoly, openPoint, srs.Pk)
+}
+
 // Responce generates an opening proof of datas using the KZG commitment scheme.
+// It panics if the proof cannot be computed, see TryResponceDatas for the error-returning version.
 
 // datas: represented as a 2D array of byte.
 // openPoint: Point at which the polynomial is opened for verification.
@@ -121,19 +191,90 @@ func ResponceDatas(
 	gamma fr.Element,
 	srs *kzg.SRS,
 ) kzg.OpeningProof {
+	proof, err := TryResponceDatas(datas, openPoint, gamma, srs)
+	if err != nil {
+		panic(err)
+	}
+	return proof
+}
+
+// TryResponceDatas generates an opening proof of datas using the KZG commitment scheme.
+// It returns the same errors as TryResponce.
+func TryResponceDatas(
+	datas [][]byte,
+	openPoint fr.Element,
+	gamma fr.Element,
+	srs *kzg.SRS,
+) (kzg.OpeningProof, error) {
 	polynomials := make([][]fr.Element, len(datas))
 	//transform the datas to polynomials
 	for i, data := range datas {
-		polynomials[i] = dataToPolynomial(data)
+		polynomials[i] = DataToPolynomial(data)
 	}
-	//transform the polynomials to a fold polynomial
-	FoldPoly := FoldedPolynomials(polynomials, gamma)
-	//calculate the proof of fold polynomial
-	proof, err := kzg.Open(FoldPoly, openPoint, srs.Pk)
-	if err != nil {
-		panic(err)
+	return TryResponce(polynomials, openPoint, gamma, srs)
+}
+
+// checkPolynomials makes sure the folded polynomial of polynomials is non-empty and can be opened with srs.
+func checkPolynomials(polynomials [][]fr.Element, srs *kzg.SRS) error {
+	largestPoly := 0
+	for i := range polynomials {
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
 	}
-	return proof
+	if largestPoly == 0 {
+		return ErrEmptyInput
+	}
+	if largestPoly > len(srs.Pk.G1) {
+		return fmt.Errorf("%w: %d coefficients, srs size %d", ErrPolynomialTooLarge, largestPoly, len(srs.Pk.G1))
+	}
+	return nil
+}
+
+// FoldSeed is the r of a challenge as Hashing.hashFold reads it: the 32-byte big-endian encoding
+// of a uint256. The contract does not reduce r modulo the scalar field, so a seed is kept as
+// bytes rather than as a field element.
+type FoldSeed [32]byte
+
+// ChallengeSeed returns the seed of the raw r of a challenge, which must be a uint256.
+func ChallengeSeed(r *big.Int) (FoldSeed, error) {
+	var seed FoldSeed
+	if r == nil || r.Sign() < 0 || r.BitLen() > 256 {
+		return seed, ErrInvalidChallengeR
+	}
+	r.FillBytes(seed[:])
+	return seed, nil
+}
+
+// GammaSeed returns the seed hashed by GetRandomHash for gamma, the seed of a challenge whose r
+// is the canonical value of gamma.
+func GammaSeed(gamma fr.Element) FoldSeed {
+	return FoldSeed(gamma.Bytes())
+}
+
+// Coefficient returns r_index = Hashing.hashFold(r, index) as a scalar. The contract multiplies
+// points by the uint256 value of the hash, which is the same as reducing it modulo the group order.
+func (s FoldSeed) Coefficient(index uint64) fr.Element {
+	var indexBytes [32]byte
+	PutUint256(indexBytes[:], index)
+	var coefficient fr.Element
+	coefficient.SetBytes(crypto.Keccak256(s[:], indexBytes[:]))
+	return coefficient
+}
+
+// ChallengeElements converts the r and point of a challenge, as stored on chain, into the seed of
+// the fold coefficients and the open point. The contract hashes r as a raw uint256, so any uint256
+// is a valid r; Verifier.verify rejects a point that is not reduced modulo the scalar field, so
+// no proof can answer a challenge with such a point.
+func ChallengeElements(r *big.Int, point *big.Int) (seed FoldSeed, openPoint fr.Element, err error) {
+	if seed, err = ChallengeSeed(r); err != nil {
+		return seed, openPoint, err
+	}
+	if point == nil || point.Sign() < 0 || point.Cmp(fr.Modulus()) >= 0 {
+		return seed, openPoint, ErrPointOutOfField
+	}
+	openPoint.SetBigInt(point)
+	return seed, openPoint, nil
 }
 
 // PutUint256 encodes an unsigned 64-bit integer v into the last 8 bytes of byte slice b.
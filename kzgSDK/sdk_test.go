@@ -1,4 +1,4 @@
-package main
+package kzgsdk
 
 import (
 	"fmt"
@@ -22,8 +22,7 @@ func TestFoldedPolynomials(t *testing.T) {
 	srs, err := kzg.NewSRS(ecc.NextPowerOfTwo(benchSize), new(big.Int).SetInt64(42))
 	assert.NoError(t, err)
 	// Create a DomiconSdk instance
-	sdk := &DomiconSdk{}
-	sdk.srs = srs
+	sdk := NewDomiconSdk(srs)
 	// Create random polynomials
 	ps := make([][]fr.Element, numPolynomials)
 	for i := 0; i < numPolynomials; i++ {
@@ -38,13 +37,13 @@ func TestFoldedPolynomials(t *testing.T) {
 
 	//sdk.AggrePoy = foldpoly
 	//compute the commitment of FoldedPolynomial
-	foldpolyCommit, err := kzg.Commit(foldpoly, sdk.srs.Pk)
+	foldpolyCommit, err := sdk.Commit(foldpoly)
 	// commitments
 	cs := make([]kzg.Digest, numPolynomials)
 	for i := 0; i < numPolynomials; i++ {
 		cs[i], _ = kzg.Commit(ps[i], srs.Pk)
 	}
-	AggreCommit, err := FoldedCommits(cs, gamma, 0, numPolynomials)
+	AggreCommit, err := sdk.Fold(cs, gamma, 0, numPolynomials)
 	equalCommit := foldpolyCommit.Equal(&AggreCommit)
 	if !equalCommit {
 		println("AggreCommit is not equal to foldpolyCommit")
@@ -56,7 +55,7 @@ func TestFoldedPolynomials(t *testing.T) {
 	open.SetRandom()
 	openProof, err := kzg.Open(foldpoly, open, sdk.srs.Pk)
 	//verify e(foldpolyCommit-openProof.ClaimValue,1)?=e(openproof.H, x-open)
-	err = kzg.Verify(&AggreCommit, &openProof, open, sdk.srs.Vk)
+	err = sdk.Verify(&AggreCommit, &openProof, open)
 	if err != nil {
 		println("AggreCommit vs openProof  failed ")
 	} else {
@@ -69,14 +68,13 @@ func TestResponce(t *testing.T) {
 	assert.NoError(t, err)
 	srs.Vk.G1 = srs.Pk.G1[0]
 	// Create a DomiconSdk instance
-	sdk := &DomiconSdk{}
-	sdk.srs = srs
+	sdk := NewDomiconSdk(srs)
 	// Create random polynomials
 	ps := make([][]fr.Element, numPolynomials)
 	cs := make([]kzg.Digest, numPolynomials)
 	for i := 0; i < numPolynomials; i++ {
 		ps[i] = randomPolynomial(benchSize / 2)
-		cs[i], _ = kzg.Commit(ps[i], sdk.srs.Pk)
+		cs[i], _ = sdk.Commit(ps[i])
 	}
 	//crate random number to fold
 	var r fr.Element
@@ -90,8 +88,12 @@ func TestResponce(t *testing.T) {
 	//calculate commits using foldfactor r
 	FoldCommit, _ := FoldedCommits(cs, gammas[0], 0, numPolynomials)
 	//Verify the correctness of the response
-	err = kzg.Verify(&FoldCommit, &proof, open, sdk.srs.Vk)
+	err = sdk.Verify(&FoldCommit, &proof, open)
 	assert.NoError(t, err)
+	//the proof computed by the sdk should match the one of Responce
+	sdkProof, err := sdk.Open(ps, open, gammas[0])
+	assert.NoError(t, err)
+	assert.Equal(t, proof, sdkProof)
 }
 func randomPolynomial(size int) []fr.Element {
 	f := make([]fr.Element, size)
@@ -144,7 +146,7 @@ func TestResponceDatas(t *testing.T) {
 	polys := make([][]fr.Element, 3)
 	cs := make([]kzg.Digest, 3)
 	for i, data := range datas {
-		polys[i] = dataToPolynomial(data)
+		polys[i] = DataToPolynomial(data)
 		cs[i], err = kzg.Commit(polys[i], srs.Pk)
 	}
 	gammaR := string("8956114444546472096905889919082729794348506031815874064517970911421382129191")
@@ -167,3 +169,55 @@ func TestResponceDatas(t *testing.T) {
 	}
 
 }
+
+func TestTryResponceErrors(t *testing.T) {
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	var open, gamma fr.Element
+	open.SetRandom()
+	gamma.SetRandom()
+
+	_, err = TryResponce(nil, open, gamma, srs)
+	assert.ErrorIs(t, err, ErrEmptyInput)
+	_, err = TryResponce([][]fr.Element{{}, {}}, open, gamma, srs)
+	assert.ErrorIs(t, err, ErrEmptyInput)
+	_, err = TryResponceDatas([][]byte{}, open, gamma, srs)
+	assert.ErrorIs(t, err, ErrEmptyInput)
+
+	//an oversized polynomial must not crash the caller
+	oversized := [][]fr.Element{randomPolynomial(len(srs.Pk.G1) + 1)}
+	_, err = TryResponce(oversized, open, gamma, srs)
+	assert.ErrorIs(t, err, ErrPolynomialTooLarge)
+	_, err = TryResponceDatas([][]byte{make([]byte, dChunkSize*(len(srs.Pk.G1)+1))}, open, gamma, srs)
+	assert.ErrorIs(t, err, ErrPolynomialTooLarge)
+	assert.Panics(t, func() { Responce(oversized, open, gamma, srs) })
+
+	proof, err := TryResponce([][]fr.Element{randomPolynomial(len(srs.Pk.G1))}, open, gamma, srs)
+	assert.NoError(t, err)
+	assert.False(t, proof.H.IsInfinity())
+}
+
+func TestChallengeElements(t *testing.T) {
+	r, _ := new(big.Int).SetString("8956114444546472096905889919082729794348506031815874064517970911421382129191", 10)
+	point, _ := new(big.Int).SetString("14717431381412684312242958025344435075661116310517857129509110506817203556416", 10)
+	seed, open, err := ChallengeElements(r, point)
+	assert.NoError(t, err)
+	var gamma fr.Element
+	gamma.SetBigInt(r)
+	assert.Equal(t, GammaSeed(gamma), seed)
+	assert.Equal(t, point, open.BigInt(new(big.Int)))
+
+	// the contract hashes r unreduced
+	large := new(big.Int).Add(r, fr.Modulus())
+	seed, _, err = ChallengeElements(large, point)
+	assert.NoError(t, err)
+	assert.Equal(t, FoldSeed(common.BigToHash(large)), seed)
+	assert.NotEqual(t, GammaSeed(gamma).Coefficient(3), seed.Coefficient(3))
+
+	_, _, err = ChallengeElements(r, fr.Modulus())
+	assert.ErrorIs(t, err, ErrPointOutOfField)
+	_, _, err = ChallengeElements(nil, point)
+	assert.ErrorIs(t, err, ErrInvalidChallengeR)
+	_, _, err = ChallengeElements(new(big.Int).Lsh(big.NewInt(1), 256), point)
+	assert.ErrorIs(t, err, ErrInvalidChallengeR)
+}
@@ -0,0 +1,120 @@
+package kzgsdk
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignatureLength is the length of the r || s || v signatures checked by CommitmentManager.
+const SignatureLength = 65
+
+var (
+	// ErrInvalidSignature is returned for a signature CommitmentManager._verifySignature rejects.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrUint256Overflow is returned when a value of the signed data is not a uint256.
+	ErrUint256Overflow = errors.New("value is not a uint256")
+)
+
+// SignatureData is the data a broadcast node signs for CommitmentManager.submitCommitment.
+type SignatureData struct {
+	// ChainID is the chain id of the CommitmentManager, the contract truncates it to a uint64.
+	ChainID uint64
+	// Target is the account submitting the commitment, tx.origin of submitCommitment.
+	Target common.Address
+	// Index is the number of commitments already submitted by Target, CommitmentManager.indices.
+	Index *big.Int
+	// Length is the length of the data in bytes.
+	Length *big.Int
+	// Timeout is the timestamp before which the commitment must be submitted.
+	Timeout    *big.Int
+	Commitment kzg.Digest
+}
+
+// Hash returns Hashing.hashData, keccak256(abi.encode(chainId, target, index, length, timeout, X, Y)).
+func (d *SignatureData) Hash() (common.Hash, error) {
+	data := make([]byte, 0, 7*32)
+	data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(d.ChainID).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(d.Target.Bytes(), 32)...)
+	for _, v := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"index", d.Index},
+		{"length", d.Length},
+		{"timeout", d.Timeout},
+	} {
+		if v.value == nil || v.value.Sign() < 0 || v.value.BitLen() > 256 {
+			return common.Hash{}, fmt.Errorf("%w: %s %v", ErrUint256Overflow, v.name, v.value)
+		}
+		data = append(data, common.LeftPadBytes(v.value.Bytes(), 32)...)
+	}
+	x, y := d.Commitment.X.Bytes(), d.Commitment.Y.Bytes()
+	data = append(data, x[:]...)
+	data = append(data, y[:]...)
+	return crypto.Keccak256Hash(data), nil
+}
+
+// SignCommitment signs data with the key of a broadcast node. The signature is r || s || v with
+// v in {27, 28}, as ecrecover expects.
+func SignCommitment(key *ecdsa.PrivateKey, data *SignatureData) ([]byte, error) {
+	hash, err := data.Hash()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	signature[SignatureLength-1] += 27
+	return signature, nil
+}
+
+// RecoverCommitmentSigner returns the address ecrecover returns for a signature of data. As for the
+// contract, the signature must be 65 bytes long, v must be 27 or 28 and r and s must be in [1, N),
+// the upper range of s being accepted.
+func RecoverCommitmentSigner(data *SignatureData, signature []byte) (common.Address, error) {
+	if len(signature) != SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: length %d", ErrInvalidSignature, len(signature))
+	}
+	v := signature[SignatureLength-1]
+	if v != 27 && v != 28 {
+		return common.Address{}, fmt.Errorf("%w: v %d", ErrInvalidSignature, v)
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	if !crypto.ValidateSignatureValues(v-27, r, s, false) {
+		return common.Address{}, fmt.Errorf("%w: r or s out of range", ErrInvalidSignature)
+	}
+	hash, err := data.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := make([]byte, SignatureLength)
+	copy(sig, signature)
+	sig[SignatureLength-1] = v - 27
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// VerifyCommitmentSignature checks a signature of data the way CommitmentManager._checkSign does,
+// returning ErrInvalidSignature unless it recovers to signer. A signature for another chain id, or
+// any other field, recovers to another address.
+func VerifyCommitmentSignature(signer common.Address, data *SignatureData, signature []byte) error {
+	recovered, err := RecoverCommitmentSigner(data, signature)
+	if err != nil {
+		return err
+	}
+	if recovered != signer {
+		return fmt.Errorf("%w: signed by %s, not %s", ErrInvalidSignature, recovered, signer)
+	}
+	return nil
+}
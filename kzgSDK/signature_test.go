@@ -0,0 +1,120 @@
+package kzgsdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignatureData(t *testing.T) *SignatureData {
+	sdk, err := NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	commitment, err := sdk.Commit(randomPolynomial(6))
+	require.NoError(t, err)
+	return &SignatureData{
+		ChainID:    11155111,
+		Target:     common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Index:      big.NewInt(3),
+		Length:     big.NewInt(1024),
+		Timeout:    big.NewInt(1718000000),
+		Commitment: commitment,
+	}
+}
+
+func TestSignatureDataHash(t *testing.T) {
+	data := newSignatureData(t)
+
+	// abi.encode(uint64, address, uint256, uint256, uint256, uint256, uint256)
+	newType := func(name string) abi.Type {
+		typ, err := abi.NewType(name, "", nil)
+		require.NoError(t, err)
+		return typ
+	}
+	uint256 := newType("uint256")
+	arguments := abi.Arguments{{Type: newType("uint64")}, {Type: newType("address")}, {Type: uint256}, {Type: uint256}, {Type: uint256}, {Type: uint256}, {Type: uint256}}
+	encoded, err := arguments.Pack(data.ChainID, data.Target, data.Index, data.Length, data.Timeout,
+		data.Commitment.X.BigInt(new(big.Int)), data.Commitment.Y.BigInt(new(big.Int)))
+	require.NoError(t, err)
+
+	hash, err := data.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash(encoded), hash)
+
+	overflow := *data
+	overflow.Length = new(big.Int).Lsh(big.NewInt(1), 256)
+	_, err = overflow.Hash()
+	assert.ErrorIs(t, err, ErrUint256Overflow)
+	overflow.Length = big.NewInt(-1)
+	_, err = overflow.Hash()
+	assert.ErrorIs(t, err, ErrUint256Overflow)
+	overflow.Length = nil
+	_, err = overflow.Hash()
+	assert.ErrorIs(t, err, ErrUint256Overflow)
+}
+
+func TestCommitmentSignature(t *testing.T) {
+	data := newSignatureData(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	signature, err := SignCommitment(key, data)
+	require.NoError(t, err)
+	require.Len(t, signature, SignatureLength)
+	assert.Contains(t, []byte{27, 28}, signature[64])
+	assert.NoError(t, VerifyCommitmentSignature(signer, data, signature))
+	recovered, err := RecoverCommitmentSigner(data, signature)
+	require.NoError(t, err)
+	assert.Equal(t, signer, recovered)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	assert.ErrorIs(t, VerifyCommitmentSignature(crypto.PubkeyToAddress(other.PublicKey), data, signature), ErrInvalidSignature)
+
+	// any other field recovers to another address
+	var one fr.Element
+	one.SetOne()
+	for name, modify := range map[string]func(d *SignatureData){
+		"chain id":   func(d *SignatureData) { d.ChainID++ },
+		"target":     func(d *SignatureData) { d.Target[0] ^= 1 },
+		"index":      func(d *SignatureData) { d.Index = big.NewInt(4) },
+		"length":     func(d *SignatureData) { d.Length = big.NewInt(1025) },
+		"timeout":    func(d *SignatureData) { d.Timeout = big.NewInt(1718000001) },
+		"commitment": func(d *SignatureData) { d.Commitment.Add(&d.Commitment, &d.Commitment) },
+	} {
+		modified := *data
+		modify(&modified)
+		assert.ErrorIs(t, VerifyCommitmentSignature(signer, &modified, signature), ErrInvalidSignature, name)
+	}
+
+	// ecrecover only takes v = 27 or 28
+	raw := append([]byte{}, signature...)
+	raw[64] -= 27
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, raw), ErrInvalidSignature)
+	raw[64] = 29
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, raw), ErrInvalidSignature)
+	// the other v recovers to another key
+	flipped := append([]byte{}, signature...)
+	flipped[64] ^= 27 ^ 28
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, flipped), ErrInvalidSignature)
+
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, signature[:64]), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, append(signature, 0)), ErrInvalidSignature)
+	zeroR := append([]byte{}, signature...)
+	copy(zeroR[:32], make([]byte, 32))
+	assert.ErrorIs(t, VerifyCommitmentSignature(signer, data, zeroR), ErrInvalidSignature)
+
+	// ecrecover does not reject the upper range of s: (r, N - s) with the other v is also valid
+	n := crypto.S256().Params().N
+	malleable := append([]byte{}, signature...)
+	s := new(big.Int).SetBytes(signature[32:64])
+	new(big.Int).Sub(n, s).FillBytes(malleable[32:64])
+	malleable[64] ^= 27 ^ 28
+	assert.NoError(t, VerifyCommitmentSignature(signer, data, malleable))
+}
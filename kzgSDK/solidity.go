@@ -0,0 +1,189 @@
+package kzgsdk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ErrSolConstantsMismatch is returned when a Constants.sol file does not match an srs.
+var ErrSolConstantsMismatch = errors.New("solidity constants do not match the srs")
+
+// deployedG2Limbs are the limbs of SRS_G2[1] in the deployed src/kzg/Constants.sol, in the order
+// of SolG2Limbs.
+var deployedG2Limbs = [4]string{
+	"12740934ba9615b77b6a49b06fcce83ce90d67b1d0e2a530069e3a7306569a91",
+	"116da8c89a0d090f3d8644ada33a5f1c8013ba7204aeca62d66d931b99afe6e7",
+	"25222d9816e5f86b4a7dedd00d04acc5c979c18bd22b834ea8c6d07c0ba441db",
+	"076441042e77b6309644b56251f059cf14befc72ac8a6157d30924e58dc4c172",
+}
+
+// DeployedVerifyingKey returns the key of src/kzg/Verifier.sol as deployed: SRS_G1[0] and
+// SRS_G2[0] are the generators, and SRS_G2[1] is the point of its Constants.sol.
+//
+// That SRS_G2[1] is not Vk.G2[1] of SRSFromSol, so the deployed Verifier rejects the proofs opened
+// with the SDK setup, but for the openings of constant polynomials. Moving the contract to the SDK
+// setup takes a new Constants.sol, written with WriteSolidityConstants, and a new deployment.
+func DeployedVerifyingKey() kzg.VerifyingKey {
+	var vk kzg.VerifyingKey
+	_, _, vk.G1, vk.G2[0] = bn254.Generators()
+	var limbs [4]*big.Int
+	for i, limb := range deployedG2Limbs {
+		limbs[i], _ = new(big.Int).SetString(limb, 16)
+	}
+	vk.G2[1].X.A1.SetBigInt(limbs[0])
+	vk.G2[1].X.A0.SetBigInt(limbs[1])
+	vk.G2[1].Y.A1.SetBigInt(limbs[2])
+	vk.G2[1].Y.A0.SetBigInt(limbs[3])
+	return vk
+}
+
+// SolG2Limbs returns the coordinates of a G2 point in the order of the EVM pairing precompile
+// and of src/kzg/Constants.sol: X = [A1, A0], Y = [A1, A0].
+func SolG2Limbs(p *bn254.G2Affine) (x [2]*big.Int, y [2]*big.Int) {
+	x[0] = p.X.A1.BigInt(new(big.Int))
+	x[1] = p.X.A0.BigInt(new(big.Int))
+	y[0] = p.Y.A1.BigInt(new(big.Int))
+	y[1] = p.Y.A0.BigInt(new(big.Int))
+	return x, y
+}
+
+// WriteSolidityConstants writes the Constants.sol contract used by src/kzg/Verifier.sol for srs.
+// The verifier only needs G1[0], so numG1 is the number of G1 powers to write, at least 1.
+func WriteSolidityConstants(w io.Writer, srs *kzg.SRS, numG1 int) error {
+	if numG1 < 1 || numG1 > len(srs.Pk.G1) {
+		return fmt.Errorf("invalid number of G1 points %d for srs of size %d", numG1, len(srs.Pk.G1))
+	}
+	g1X := make([]*big.Int, numG1)
+	g1Y := make([]*big.Int, numG1)
+	for i := 0; i < numG1; i++ {
+		g1X[i] = srs.Pk.G1[i].X.BigInt(new(big.Int))
+		g1Y[i] = srs.Pk.G1[i].Y.BigInt(new(big.Int))
+	}
+	var g2X0, g2X1, g2Y0, g2Y1 []*big.Int
+	for i := range srs.Vk.G2 {
+		x, y := SolG2Limbs(&srs.Vk.G2[i])
+		g2X0 = append(g2X0, x[0])
+		g2X1 = append(g2X1, x[1])
+		g2Y0 = append(g2Y0, y[0])
+		g2Y1 = append(g2Y1, y[1])
+	}
+
+	var sb strings.Builder
+	sb.WriteString("pragma solidity ^0.8.0;\n\n")
+	sb.WriteString("import \"./Pairing.sol\";\n\n")
+	sb.WriteString("contract Constants {\n")
+	sb.WriteString("    using Pairing for *;\n\n")
+	fmt.Fprintf(&sb, "    uint256 constant PRIME_Q = %s;\n", fp.Modulus())
+	fmt.Fprintf(&sb, "    uint256 constant BABYJUB_P = %s;\n", fr.Modulus())
+	for _, array := range []struct {
+		name   string
+		values []*big.Int
+	}{
+		{"SRS_G1_X", g1X},
+		{"SRS_G1_Y", g1Y},
+		{"SRS_G2_X_0", g2X0},
+		{"SRS_G2_X_1", g2X1},
+		{"SRS_G2_Y_0", g2Y0},
+		{"SRS_G2_Y_1", g2Y1},
+	} {
+		writeSolArray(&sb, array.name, array.values)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeSolArray writes a uint256[] state variable the way forge fmt lays it out.
+func writeSolArray(sb *strings.Builder, name string, values []*big.Int) {
+	if len(values) == 1 {
+		fmt.Fprintf(sb, "\n    uint256[] %s = [uint256(0x%064x)];\n", name, values[0])
+		return
+	}
+	fmt.Fprintf(sb, "\n    uint256[] %s = [\n", name)
+	for i, v := range values {
+		sep := ","
+		if i == len(values)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(sb, "        uint256(0x%064x)%s\n", v, sep)
+	}
+	sb.WriteString("    ];\n")
+}
+
+var (
+	solConstantRegexp = regexp.MustCompile(`uint256\s+constant\s+(\w+)\s*=\s*(\w+)\s*;`)
+	solArrayRegexp    = regexp.MustCompile(`uint256\[\]\s+(\w+)\s*=\s*\[([^\]]*)\]\s*;`)
+	solElementRegexp  = regexp.MustCompile(`uint256\(\s*(\w+)\s*\)`)
+)
+
+// CheckSolidityConstants parses a Constants.sol file and checks that it matches srs:
+// PRIME_Q and BABYJUB_P are the BN254 moduli, every SRS_G1 point is the G1 power of the
+// same index and the SRS_G2 points are Vk.G2 in the limb order of the pairing precompile.
+func CheckSolidityConstants(r io.Reader, srs *kzg.SRS) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	constants := make(map[string]*big.Int)
+	for _, m := range solConstantRegexp.FindAllStringSubmatch(string(content), -1) {
+		v, ok := new(big.Int).SetString(m[2], 0)
+		if !ok {
+			return fmt.Errorf("%w: invalid %s", ErrSolConstantsMismatch, m[1])
+		}
+		constants[m[1]] = v
+	}
+	arrays := make(map[string][]*big.Int)
+	for _, m := range solArrayRegexp.FindAllStringSubmatch(string(content), -1) {
+		for _, e := range solElementRegexp.FindAllStringSubmatch(m[2], -1) {
+			v, ok := new(big.Int).SetString(e[1], 0)
+			if !ok {
+				return fmt.Errorf("%w: invalid element %s in %s", ErrSolConstantsMismatch, e[1], m[1])
+			}
+			arrays[m[1]] = append(arrays[m[1]], v)
+		}
+	}
+
+	if v := constants["PRIME_Q"]; v == nil || v.Cmp(fp.Modulus()) != 0 {
+		return fmt.Errorf("%w: PRIME_Q", ErrSolConstantsMismatch)
+	}
+	if v := constants["BABYJUB_P"]; v == nil || v.Cmp(fr.Modulus()) != 0 {
+		return fmt.Errorf("%w: BABYJUB_P", ErrSolConstantsMismatch)
+	}
+
+	g1X, g1Y := arrays["SRS_G1_X"], arrays["SRS_G1_Y"]
+	if len(g1X) == 0 || len(g1X) != len(g1Y) || len(g1X) > len(srs.Pk.G1) {
+		return fmt.Errorf("%w: SRS_G1 has %d/%d points", ErrSolConstantsMismatch, len(g1X), len(g1Y))
+	}
+	for i := range g1X {
+		if g1X[i].Cmp(srs.Pk.G1[i].X.BigInt(new(big.Int))) != 0 ||
+			g1Y[i].Cmp(srs.Pk.G1[i].Y.BigInt(new(big.Int))) != 0 {
+			return fmt.Errorf("%w: SRS_G1[%d]", ErrSolConstantsMismatch, i)
+		}
+	}
+
+	names := [4]string{"SRS_G2_X_0", "SRS_G2_X_1", "SRS_G2_Y_0", "SRS_G2_Y_1"}
+	for _, name := range names {
+		if len(arrays[name]) != len(srs.Vk.G2) {
+			return fmt.Errorf("%w: %s has %d points", ErrSolConstantsMismatch, name, len(arrays[name]))
+		}
+	}
+	for i := range srs.Vk.G2 {
+		x, y := SolG2Limbs(&srs.Vk.G2[i])
+		limbs := [4]*big.Int{x[0], x[1], y[0], y[1]}
+		for j, name := range names {
+			if arrays[name][i].Cmp(limbs[j]) != 0 {
+				return fmt.Errorf("%w: %s[%d]", ErrSolConstantsMismatch, name, i)
+			}
+		}
+	}
+	return nil
+}
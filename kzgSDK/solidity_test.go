@@ -0,0 +1,85 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+const solConstantsPath = "../src/kzg/Constants.sol"
+
+// deployedSRS is the srs whose constants are those of the deployed Constants.sol.
+func deployedSRS() *kzg.SRS {
+	vk := DeployedVerifyingKey()
+	return &kzg.SRS{Pk: kzg.ProvingKey{G1: []bn254.G1Affine{vk.G1}}, Vk: vk}
+}
+
+func TestSolidityConstantsUpToDate(t *testing.T) {
+	file, err := os.ReadFile(solConstantsPath)
+	assert.NoError(t, err)
+	assert.NoError(t, CheckSolidityConstants(bytes.NewReader(file), deployedSRS()))
+
+	// the checked-in contract is the output of the generator
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSolidityConstants(&buf, deployedSRS(), 1))
+	assert.Equal(t, string(file), buf.String())
+}
+
+func TestDeployedVerifyingKey(t *testing.T) {
+	vk := DeployedVerifyingKey()
+	assert.True(t, vk.G2[1].IsOnCurve())
+	assert.True(t, vk.G2[1].IsInSubGroup())
+
+	// the deployed contract shares the generators of the SDK setup, but not its SRS_G2[1]
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	assert.Equal(t, srs.Vk.G1, vk.G1)
+	assert.Equal(t, srs.Vk.G2[0], vk.G2[0])
+	assert.NotEqual(t, srs.Vk.G2[1], vk.G2[1])
+	file, err := os.ReadFile(solConstantsPath)
+	assert.NoError(t, err)
+	err = CheckSolidityConstants(bytes.NewReader(file), srs)
+	assert.ErrorIs(t, err, ErrSolConstantsMismatch)
+	assert.Contains(t, err.Error(), "SRS_G2_X_0[1]")
+}
+
+func TestWriteSolidityConstants(t *testing.T) {
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(t, err)
+	for _, numG1 := range []int{1, 3, 16} {
+		var buf bytes.Buffer
+		assert.NoError(t, WriteSolidityConstants(&buf, srs, numG1))
+		assert.NoError(t, CheckSolidityConstants(&buf, srs))
+	}
+	assert.Error(t, WriteSolidityConstants(&bytes.Buffer{}, srs, 0))
+	assert.Error(t, WriteSolidityConstants(&bytes.Buffer{}, srs, 17))
+
+	// the constants of another srs
+	solSrs, err := SRSFromSol()
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSolidityConstants(&buf, srs, 1))
+	err = CheckSolidityConstants(bytes.NewReader(buf.Bytes()), solSrs)
+	assert.ErrorIs(t, err, ErrSolConstantsMismatch)
+	assert.Contains(t, err.Error(), "SRS_G2_X_0[1]")
+
+	// swapped Fp2 limbs
+	x, _ := SolG2Limbs(&srs.Vk.G2[1])
+	swapped := strings.Replace(buf.String(), x[0].Text(16), "tmp", 1)
+	swapped = strings.Replace(swapped, x[1].Text(16), x[0].Text(16), 1)
+	swapped = strings.Replace(swapped, "tmp", x[1].Text(16), 1)
+	err = CheckSolidityConstants(strings.NewReader(swapped), srs)
+	assert.ErrorIs(t, err, ErrSolConstantsMismatch)
+
+	// wrong scalar field modulus
+	wrongP := strings.Replace(buf.String(), "BABYJUB_P = 2", "BABYJUB_P = 3", 1)
+	err = CheckSolidityConstants(strings.NewReader(wrongP), srs)
+	assert.ErrorIs(t, err, ErrSolConstantsMismatch)
+	assert.Contains(t, err.Error(), "BABYJUB_P")
+}
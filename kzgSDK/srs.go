@@ -0,0 +1,225 @@
+package kzgsdk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/keycard-go/hexutils"
+)
+
+var (
+	// ErrSRSDigestMismatch is returned when the digest recorded in an srs file does not match its content.
+	ErrSRSDigestMismatch = errors.New("srs digest mismatch")
+	// ErrSRSGeneratorMismatch is returned when the generators of an srs differ from the ones of the Solidity verifier.
+	ErrSRSGeneratorMismatch = errors.New("srs generators do not match the Solidity constants")
+)
+
+// Generators of src/kzg/Constants.sol. The G2 coordinates are stored there as [A1, A0],
+// the order expected by the EVM pairing precompile.
+const (
+	solG1X   = "0000000000000000000000000000000000000000000000000000000000000001"
+	solG1Y   = "0000000000000000000000000000000000000000000000000000000000000002"
+	solG2X_0 = "198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2"
+	solG2X_1 = "1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed"
+	solG2Y_0 = "090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b"
+	solG2Y_1 = "12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa"
+)
+
+// SolGenerators returns the G1 and G2 generators used by the Solidity verifier.
+func SolGenerators() (bn254.G1Affine, bn254.G2Affine) {
+	var g1 bn254.G1Affine
+	g1.X.SetBytes(hexutils.HexToBytes(solG1X))
+	g1.Y.SetBytes(hexutils.HexToBytes(solG1Y))
+
+	var g2 bn254.G2Affine
+	g2.X.A0.SetBytes(hexutils.HexToBytes(solG2X_1))
+	g2.X.A1.SetBytes(hexutils.HexToBytes(solG2X_0))
+	g2.Y.A0.SetBytes(hexutils.HexToBytes(solG2Y_1))
+	g2.Y.A1.SetBytes(hexutils.HexToBytes(solG2Y_0))
+	return g1, g2
+}
+
+// WriteSRS writes the binary encoding of srs followed by the keccak256 digest of that encoding.
+func WriteSRS(w io.Writer, srs *kzg.SRS) error {
+	hasher := crypto.NewKeccakState()
+	if _, err := srs.WriteTo(io.MultiWriter(w, hasher)); err != nil {
+		return err
+	}
+	_, err := w.Write(hasher.Sum(nil))
+	return err
+}
+
+// LoadSRS reads an srs file written by WriteSRS or GenerateSRSFile, see LoadSRSFromReader.
+func LoadSRS(path string) (*kzg.SRS, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadSRSFromReader(bufio.NewReader(file))
+}
+
+// LoadSRSFromReader decodes an srs written by WriteSRS.
+// It rejects the srs if the recorded digest does not match the decoded bytes, or if ValidateSRS fails.
+func LoadSRSFromReader(r io.Reader) (*kzg.SRS, error) {
+	hasher := crypto.NewKeccakState()
+	srs := new(kzg.SRS)
+	if _, err := srs.ReadFrom(io.TeeReader(r, hasher)); err != nil {
+		return nil, err
+	}
+
+	var recorded common.Hash
+	if _, err := io.ReadFull(r, recorded[:]); err != nil {
+		return nil, fmt.Errorf("%w: missing digest: %v", ErrSRSDigestMismatch, err)
+	}
+	if !bytes.Equal(recorded[:], hasher.Sum(nil)) {
+		return nil, ErrSRSDigestMismatch
+	}
+	// nothing may follow the digest
+	if n, _ := r.Read(make([]byte, 1)); n != 0 {
+		return nil, fmt.Errorf("%w: trailing bytes after digest", ErrSRSDigestMismatch)
+	}
+
+	if err := ValidateSRS(srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
+
+var (
+	// ErrSRSNotOnCurve is returned by ValidateSRS when a point is not on the curve.
+	ErrSRSNotOnCurve = errors.New("srs point is not on the curve")
+	// ErrSRSNotInSubgroup is returned by ValidateSRS when a point is not in the prime order subgroup.
+	ErrSRSNotInSubgroup = errors.New("srs point is not in the subgroup")
+	// ErrSRSInconsistent is returned by ValidateSRS when the G1 powers do not match Vk.G2[1].
+	ErrSRSInconsistent = errors.New("srs powers are not consistent")
+)
+
+// SRSError reports the first point of an srs rejected by ValidateSRS.
+type SRSError struct {
+	// Point names the rejected part of the srs: "Pk.G1", "Vk.G1" or "Vk.G2".
+	Point string
+	// Index of the rejected point in Point.
+	Index int
+	// Err is one of ErrSRSNotOnCurve, ErrSRSNotInSubgroup, ErrSRSGeneratorMismatch or ErrSRSInconsistent.
+	Err error
+}
+
+func (e *SRSError) Error() string {
+	return fmt.Sprintf("%v: %s[%d]", e.Err, e.Point, e.Index)
+}
+
+func (e *SRSError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateSRS checks that srs is a well formed powers-of-tau setup for the Solidity verifier:
+//
+//	every point is on the curve and in the prime order subgroup,
+//	Pk.G1[0], Vk.G1 and Vk.G2[0] are the generators of src/kzg/Constants.sol,
+//	e(G1[i+1], G2[0]) == e(G1[i], G2[1]) for all i.
+//
+// Vk.G2[1] is checked against G1[1] first. The other pairing equations are then checked all at
+// once with a random linear combination, and a failing batch is bisected to find the first
+// inconsistent power. The returned error is an *SRSError.
+func ValidateSRS(srs *kzg.SRS) error {
+	if len(srs.Pk.G1) < 2 {
+		return &SRSError{Point: "Pk.G1", Index: len(srs.Pk.G1), Err: kzg.ErrMinSRSSize}
+	}
+	for i := range srs.Pk.G1 {
+		if !srs.Pk.G1[i].IsOnCurve() {
+			return &SRSError{Point: "Pk.G1", Index: i, Err: ErrSRSNotOnCurve}
+		}
+		if !srs.Pk.G1[i].IsInSubGroup() {
+			return &SRSError{Point: "Pk.G1", Index: i, Err: ErrSRSNotInSubgroup}
+		}
+	}
+	if !srs.Vk.G1.IsOnCurve() {
+		return &SRSError{Point: "Vk.G1", Err: ErrSRSNotOnCurve}
+	}
+	for i := range srs.Vk.G2 {
+		if !srs.Vk.G2[i].IsOnCurve() {
+			return &SRSError{Point: "Vk.G2", Index: i, Err: ErrSRSNotOnCurve}
+		}
+		if !srs.Vk.G2[i].IsInSubGroup() {
+			return &SRSError{Point: "Vk.G2", Index: i, Err: ErrSRSNotInSubgroup}
+		}
+	}
+
+	g1, g2 := SolGenerators()
+	if !srs.Pk.G1[0].Equal(&g1) {
+		return &SRSError{Point: "Pk.G1", Index: 0, Err: ErrSRSGeneratorMismatch}
+	}
+	if !srs.Vk.G1.Equal(&g1) {
+		return &SRSError{Point: "Vk.G1", Index: 0, Err: ErrSRSGeneratorMismatch}
+	}
+	if !srs.Vk.G2[0].Equal(&g2) {
+		return &SRSError{Point: "Vk.G2", Index: 0, Err: ErrSRSGeneratorMismatch}
+	}
+
+	// G1[0] is the generator, so the first equation relates G1[1] and G2[1] only: a mismatch is
+	// reported on G2[1], which pairs with every power, rather than on all of Pk.G1
+	ok, err := checkPowers(srs, 1)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &SRSError{Point: "Vk.G2", Index: 1, Err: ErrSRSInconsistent}
+	}
+	// the relation between G1[i] and G1[i+1] holds for every i < n-1
+	ok, err = checkPowers(srs, len(srs.Pk.G1)-1)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	// find the smallest prefix that fails, its last power is the first bad one
+	low, high := 1, len(srs.Pk.G1)-1
+	for low+1 < high {
+		mid := (low + high) / 2
+		ok, err = checkPowers(srs, mid)
+		if err != nil {
+			return err
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return &SRSError{Point: "Pk.G1", Index: high, Err: ErrSRSInconsistent}
+}
+
+// checkPowers checks e(G1[i+1], G2[0]) == e(G1[i], G2[1]) for all i < n using random coefficients ρᵢ:
+// e(∑ᵢρᵢG1[i+1], G2[0]) · e(-∑ᵢρᵢG1[i], G2[1]) == 1
+func checkPowers(srs *kzg.SRS, n int) (bool, error) {
+	rhos := make([]fr.Element, n)
+	for i := range rhos {
+		if _, err := rhos[i].SetRandom(); err != nil {
+			return false, err
+		}
+	}
+	var shifted, unshifted bn254.G1Affine
+	if _, err := shifted.MultiExp(srs.Pk.G1[1:n+1], rhos, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	if _, err := unshifted.MultiExp(srs.Pk.G1[:n], rhos, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	unshifted.Neg(&unshifted)
+	return bn254.PairingCheck(
+		[]bn254.G1Affine{shifted, unshifted},
+		[]bn254.G2Affine{srs.Vk.G2[0], srs.Vk.G2[1]},
+	)
+}
@@ -0,0 +1,132 @@
+package kzgsdk
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSRS(t *testing.T) {
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "srs")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	assert.NoError(t, WriteSRS(file, srs))
+	assert.NoError(t, file.Close())
+
+	loaded, err := LoadSRS(path)
+	assert.NoError(t, err)
+	assert.Equal(t, srs.Pk.G1, loaded.Pk.G1)
+	assert.Equal(t, srs.Vk, loaded.Vk)
+
+	//a proof computed with the cached srs verifies against the Solidity srs
+	ps := ConstPolys()
+	var open fr.Element
+	open.SetRandom()
+	proof, err := kzg.Open(ps[0], open, loaded.Pk)
+	assert.NoError(t, err)
+	commit, err := kzg.Commit(ps[0], srs.Pk)
+	assert.NoError(t, err)
+	assert.NoError(t, kzg.Verify(&commit, &proof, open, srs.Vk))
+}
+
+func TestLoadSRSFromReaderCorrupted(t *testing.T) {
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSRS(&buf, srs))
+	encoded := buf.Bytes()
+
+	// flip a bit of the last digest byte
+	corrupted := bytes.Clone(encoded)
+	corrupted[len(corrupted)-1] ^= 1
+	_, err = LoadSRSFromReader(bytes.NewReader(corrupted))
+	assert.ErrorIs(t, err, ErrSRSDigestMismatch)
+
+	// truncated digest
+	_, err = LoadSRSFromReader(bytes.NewReader(encoded[:len(encoded)-1]))
+	assert.ErrorIs(t, err, ErrSRSDigestMismatch)
+
+	// trailing bytes
+	_, err = LoadSRSFromReader(bytes.NewReader(append(bytes.Clone(encoded), 0)))
+	assert.ErrorIs(t, err, ErrSRSDigestMismatch)
+
+	// a valid file that does not use the Solidity generators
+	other, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(t, err)
+	other.Pk.G1[0] = other.Pk.G1[1]
+	other.Vk.G1 = other.Pk.G1[1]
+	buf.Reset()
+	assert.NoError(t, WriteSRS(&buf, other))
+	_, err = LoadSRSFromReader(&buf)
+	assert.ErrorIs(t, err, ErrSRSGeneratorMismatch)
+}
+
+func TestValidateSRS(t *testing.T) {
+	srs, err := SRSFromSol()
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateSRS(srs))
+
+	generated, err := kzg.NewSRS(64, big.NewInt(42))
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateSRS(generated))
+
+	var srsErr *SRSError
+
+	// replace a power by another valid point
+	tampered, _ := SRSFromSol()
+	tampered.Pk.G1[37] = tampered.Pk.G1[36]
+	err = ValidateSRS(tampered)
+	assert.ErrorIs(t, err, ErrSRSInconsistent)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, "Pk.G1", srsErr.Point)
+	assert.Equal(t, 37, srsErr.Index)
+
+	tampered, _ = SRSFromSol()
+	tampered.Pk.G1[len(tampered.Pk.G1)-1] = tampered.Pk.G1[1]
+	err = ValidateSRS(tampered)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, "Pk.G1", srsErr.Point)
+	assert.Equal(t, len(tampered.Pk.G1)-1, srsErr.Index)
+
+	// a point that is not on the curve
+	tampered, _ = SRSFromSol()
+	tampered.Pk.G1[3].Y.SetOne()
+	err = ValidateSRS(tampered)
+	assert.ErrorIs(t, err, ErrSRSNotOnCurve)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, "Pk.G1", srsErr.Point)
+	assert.Equal(t, 3, srsErr.Index)
+
+	// a setup that does not start at the generator
+	tampered, _ = SRSFromSol()
+	tampered.Pk.G1 = tampered.Pk.G1[1:]
+	err = ValidateSRS(tampered)
+	assert.ErrorIs(t, err, ErrSRSGeneratorMismatch)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, 0, srsErr.Index)
+
+	// G2[1] of another setup
+	tampered, _ = SRSFromSol()
+	tampered.Vk.G2[1] = generated.Vk.G2[1]
+	err = ValidateSRS(tampered)
+	assert.ErrorIs(t, err, ErrSRSInconsistent)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, "Vk.G2", srsErr.Point)
+	assert.Equal(t, 1, srsErr.Index)
+
+	// a bad power after the first one is still reported on Pk.G1
+	tampered, _ = SRSFromSol()
+	tampered.Pk.G1[2] = tampered.Pk.G1[1]
+	err = ValidateSRS(tampered)
+	assert.ErrorAs(t, err, &srsErr)
+	assert.Equal(t, "Pk.G1", srsErr.Point)
+	assert.Equal(t, 2, srsErr.Index)
+}
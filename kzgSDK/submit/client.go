@@ -0,0 +1,217 @@
+package submit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultSignTimeout is the time a broadcast node has to answer a SignRequest.
+const DefaultSignTimeout = 10 * time.Second
+
+var (
+	// ErrNotEnoughSignatures is returned when fewer broadcast nodes than the node group requires
+	// returned a valid signature.
+	ErrNotEnoughSignatures = errors.New("not enough signatures")
+	// ErrUnknownNode is reported for an address of the node group the client has no Node for.
+	ErrUnknownNode = errors.New("unknown broadcast node")
+	// ErrInvalidNodeGroup is returned for a node group the StorageManager could not have stored.
+	ErrInvalidNodeGroup = errors.New("invalid node group")
+	// ErrNotBroadcastNode is reported for an address of the node group that is no longer a broadcast node.
+	ErrNotBroadcastNode = errors.New("not a broadcast node")
+)
+
+// ClientConfig configures a Client.
+type ClientConfig struct {
+	// ChainID is the chain id of the CommitmentManager, part of what the nodes sign.
+	ChainID uint64
+	// SignTimeout bounds the time each node has to sign, DefaultSignTimeout if zero.
+	SignTimeout time.Duration
+}
+
+// Client collects the signatures of a node group for the commitment of a blob.
+type Client struct {
+	sdk    *kzgsdk.DomiconSdk
+	nodes  map[common.Address]Node
+	config ClientConfig
+}
+
+// NewClient returns a Client asking nodes[addr] for the signature of the broadcast node addr.
+func NewClient(sdk *kzgsdk.DomiconSdk, nodes map[common.Address]Node, config ClientConfig) *Client {
+	if config.SignTimeout == 0 {
+		config.SignTimeout = DefaultSignTimeout
+	}
+	return &Client{sdk: sdk, nodes: maps.Clone(nodes), config: config}
+}
+
+// Request describes a commitment to submit.
+type Request struct {
+	// Data is the blob, its length in bytes is the length of the commitment.
+	Data []byte
+	// Sender is the account sending submitCommitment, tx.origin for the contract.
+	Sender common.Address
+	// Index is CommitmentManager.indices(Sender) when the transaction executes.
+	Index *big.Int
+	// Timeout is the timestamp before which the transaction must execute.
+	Timeout *big.Int
+	// NameSpaceKey is the namespace of the blob, zero for none.
+	NameSpaceKey [32]byte
+	// NodeGroupKey and NodeGroup are the node group signing the commitment, as StorageManager.NODEGROUP returns it.
+	NodeGroupKey [32]byte
+	NodeGroup    contracts.NodeGroup
+	// BaseFee is CommitmentManager.baseFee, the fee per byte.
+	BaseFee *big.Int
+	// IsNodeBroadcast reports whether an address is a broadcast node, as NodeManager.isNodeBroadcast
+	// does when the transaction executes. The IsNodeBroadcast method of a contracts.NodeSnapshot
+	// of the latest block fits.
+	IsNodeBroadcast func(addr common.Address) bool
+}
+
+// Submission is a commitment ready to be submitted.
+type Submission struct {
+	Commitment kzg.Digest
+	Length     *big.Int
+	// Signatures[i] is the signature of NodeGroup.Addrs[i], empty if the node did not sign.
+	Signatures [][]byte
+	// Signed is the number of valid signatures.
+	Signed int
+	// Failures holds the error of each node that did not sign.
+	Failures map[common.Address]error
+	// Fee is the value of the transaction, baseFee * length.
+	Fee *big.Int
+	// Calldata is the calldata of submitCommitment.
+	Calldata []byte
+}
+
+// Collect commits to the data and asks every broadcast node of the group for its signature
+// concurrently, each node having SignTimeout to answer. The signatures are checked as _checkSign
+// checks them and placed in the order of NodeGroup.Addrs, the order of sortAddresses; the entry of
+// a node that failed is left empty so that the contract skips it. The contract also skips the
+// nodes that are no longer broadcast nodes, which are not asked and fail with ErrNotBroadcastNode.
+// Collect returns ErrNotEnoughSignatures, wrapping the failures of the nodes, if fewer than
+// requiredAmountOfSignatures nodes signed.
+func (c *Client) Collect(ctx context.Context, request *Request) (*Submission, error) {
+	group := request.NodeGroup
+	if len(group.Addrs) == 0 {
+		return nil, fmt.Errorf("%w: no address", ErrInvalidNodeGroup)
+	}
+	if !slices.Equal(group.Addrs, contracts.SortAddresses(group.Addrs)) {
+		return nil, fmt.Errorf("%w: addresses are not sorted", ErrInvalidNodeGroup)
+	}
+	if request.BaseFee == nil || request.BaseFee.Sign() < 0 {
+		return nil, fmt.Errorf("invalid base fee %v", request.BaseFee)
+	}
+	if request.IsNodeBroadcast == nil {
+		return nil, errors.New("no IsNodeBroadcast to check the broadcast nodes")
+	}
+	commitment, err := c.sdk.CommitData(request.Data)
+	if err != nil {
+		return nil, err
+	}
+	length := big.NewInt(int64(len(request.Data)))
+	signRequest := &SignRequest{
+		Data: request.Data,
+		SignatureData: kzgsdk.SignatureData{
+			ChainID:    c.config.ChainID,
+			Target:     request.Sender,
+			Index:      request.Index,
+			Length:     length,
+			Timeout:    request.Timeout,
+			Commitment: commitment,
+		},
+	}
+	if _, err := signRequest.SignatureData.Hash(); err != nil {
+		return nil, err
+	}
+
+	signatures := make([][]byte, len(group.Addrs))
+	failures := make([]error, len(group.Addrs))
+	var wg sync.WaitGroup
+	for i, addr := range group.Addrs {
+		if !request.IsNodeBroadcast(addr) {
+			failures[i] = ErrNotBroadcastNode
+			continue
+		}
+		node, ok := c.nodes[addr]
+		if !ok {
+			failures[i] = ErrUnknownNode
+			continue
+		}
+		wg.Add(1)
+		go func(i int, addr common.Address, node Node) {
+			defer wg.Done()
+			signatures[i], failures[i] = c.sign(ctx, addr, node, signRequest)
+		}(i, addr, node)
+	}
+	wg.Wait()
+
+	submission := &Submission{
+		Commitment: commitment,
+		Length:     length,
+		Signatures: signatures,
+		Failures:   make(map[common.Address]error),
+		Fee:        new(big.Int).Mul(request.BaseFee, length),
+	}
+	var errs []error
+	for i, addr := range group.Addrs {
+		if failures[i] != nil {
+			signatures[i] = []byte{}
+			submission.Failures[addr] = failures[i]
+			errs = append(errs, fmt.Errorf("%s: %w", addr, failures[i]))
+			continue
+		}
+		submission.Signed++
+	}
+	required := group.RequiredAmountOfSignatures
+	if required == nil || big.NewInt(int64(submission.Signed)).Cmp(required) < 0 {
+		return nil, fmt.Errorf("%w: %d of %v: %w", ErrNotEnoughSignatures, submission.Signed, required, errors.Join(errs...))
+	}
+
+	submission.Calldata, err = contracts.PackSubmitCommitment(
+		length, request.Timeout, request.NameSpaceKey, request.NodeGroupKey, signatures, contracts.G1PointFromDigest(&commitment),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+// sign asks a node for its signature and checks it. It gives up after SignTimeout even if the
+// node does not return.
+func (c *Client) sign(ctx context.Context, addr common.Address, node Node, request *SignRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.SignTimeout)
+	defer cancel()
+	type result struct {
+		signature []byte
+		err       error
+	}
+	results := make(chan result, 1)
+	go func() {
+		signature, err := node.Sign(ctx, request)
+		results <- result{signature, err}
+	}()
+	var signature []byte
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-results:
+		if r.err != nil {
+			return nil, r.err
+		}
+		signature = r.signature
+	}
+	if err := kzgsdk.VerifyCommitmentSignature(addr, &request.SignatureData, signature); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
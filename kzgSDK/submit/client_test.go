@@ -0,0 +1,195 @@
+package submit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/domicon-labs/dataAuditingkzg-sdk/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bigInt(v int64) *big.Int {
+	return big.NewInt(v)
+}
+
+// stalledNode only answers once its context is done.
+type stalledNode struct{}
+
+func (stalledNode) Sign(ctx context.Context, _ *SignRequest) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// broadcastNodes returns an IsNodeBroadcast reporting the addresses as broadcast nodes.
+func broadcastNodes(addrs ...common.Address) func(common.Address) bool {
+	return func(addr common.Address) bool {
+		return slices.Contains(addrs, addr)
+	}
+}
+
+// nodeFunc adapts a function to a Node.
+type nodeFunc func(ctx context.Context, request *SignRequest) ([]byte, error)
+
+func (f nodeFunc) Sign(ctx context.Context, request *SignRequest) ([]byte, error) {
+	return f(ctx, request)
+}
+
+func randomAddress(t *testing.T) common.Address {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func sortedGroup(required int64, addrs ...common.Address) contracts.NodeGroup {
+	return contracts.NodeGroup{RequiredAmountOfSignatures: bigInt(required), Addrs: contracts.SortAddresses(addrs)}
+}
+
+func TestClientCollect(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+
+	nodes := make(map[common.Address]Node)
+	var honest []common.Address
+	for i := 0; i < 3; i++ {
+		node := newLocalNode(t, sdk)
+		nodes[node.Address()] = node
+		honest = append(honest, node.Address())
+	}
+	stalled := randomAddress(t)
+	nodes[stalled] = stalledNode{}
+	// a node signing with another key
+	impostor, other := randomAddress(t), newLocalNode(t, sdk)
+	nodes[impostor] = other
+	failing := randomAddress(t)
+	nodes[failing] = nodeFunc(func(context.Context, *SignRequest) ([]byte, error) {
+		return nil, errors.New("node is down")
+	})
+	missing := randomAddress(t)
+	// a node that left the broadcast nodes, whose signature the contract would skip
+	unstaked := newLocalNode(t, sdk)
+	nodes[unstaked.Address()] = unstaked
+	group := sortedGroup(3, append(honest, stalled, impostor, failing, missing, unstaked.Address())...)
+
+	client := NewClient(sdk, nodes, ClientConfig{ChainID: 5, SignTimeout: 100 * time.Millisecond})
+	request := &Request{
+		Data:            bytes.Repeat([]byte("multiadaptive"), 40),
+		Sender:          randomAddress(t),
+		Index:           bigInt(7),
+		Timeout:         bigInt(1718000000),
+		NameSpaceKey:    [32]byte{1},
+		NodeGroupKey:    [32]byte{2},
+		NodeGroup:       group,
+		BaseFee:         bigInt(1000),
+		IsNodeBroadcast: broadcastNodes(append(honest, stalled, impostor, failing, missing)...),
+	}
+	submission, err := client.Collect(ctx, request)
+	require.NoError(t, err)
+
+	commitment, err := sdk.CommitData(request.Data)
+	require.NoError(t, err)
+	assert.Equal(t, commitment, submission.Commitment)
+	assert.Equal(t, bigInt(520), submission.Length)
+	assert.Equal(t, bigInt(520000), submission.Fee)
+	assert.Equal(t, 3, submission.Signed)
+	assert.ErrorIs(t, submission.Failures[stalled], context.DeadlineExceeded)
+	assert.ErrorIs(t, submission.Failures[impostor], kzgsdk.ErrInvalidSignature)
+	assert.ErrorIs(t, submission.Failures[missing], ErrUnknownNode)
+	assert.Error(t, submission.Failures[failing])
+	assert.ErrorIs(t, submission.Failures[unstaked.Address()], ErrNotBroadcastNode)
+	assert.Len(t, submission.Failures, 5)
+
+	signed := kzgsdk.SignatureData{
+		ChainID:    5,
+		Target:     request.Sender,
+		Index:      request.Index,
+		Length:     submission.Length,
+		Timeout:    request.Timeout,
+		Commitment: commitment,
+	}
+	require.Len(t, submission.Signatures, len(group.Addrs))
+	for i, addr := range group.Addrs {
+		if slices.Contains(honest, addr) {
+			assert.NoError(t, kzgsdk.VerifyCommitmentSignature(addr, &signed, submission.Signatures[i]))
+		} else {
+			assert.Empty(t, submission.Signatures[i])
+		}
+	}
+
+	method := contracts.CommitmentManagerABI.Methods["submitCommitment"]
+	assert.Equal(t, method.ID, submission.Calldata[:4])
+	args, err := method.Inputs.Unpack(submission.Calldata[4:])
+	require.NoError(t, err)
+	assert.Equal(t, submission.Length, args[0])
+	assert.Equal(t, request.Timeout, args[1])
+	assert.Equal(t, request.NameSpaceKey, args[2])
+	assert.Equal(t, request.NodeGroupKey, args[3])
+	assert.Equal(t, submission.Signatures, args[4])
+	expected, err := contracts.PackSubmitCommitment(submission.Length, request.Timeout, request.NameSpaceKey,
+		request.NodeGroupKey, submission.Signatures, contracts.G1PointFromDigest(&commitment))
+	require.NoError(t, err)
+	assert.Equal(t, expected, submission.Calldata)
+}
+
+func TestClientCollectErrors(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	node := newLocalNode(t, sdk)
+	missing := randomAddress(t)
+	client := NewClient(sdk, map[common.Address]Node{node.Address(): node}, ClientConfig{ChainID: 5})
+	request := &Request{
+		Data:            []byte("blob"),
+		Sender:          randomAddress(t),
+		Index:           bigInt(0),
+		Timeout:         bigInt(100),
+		NodeGroup:       sortedGroup(2, node.Address(), missing),
+		BaseFee:         bigInt(1),
+		IsNodeBroadcast: broadcastNodes(node.Address(), missing),
+	}
+
+	_, err := client.Collect(ctx, request)
+	assert.ErrorIs(t, err, ErrNotEnoughSignatures)
+	assert.ErrorIs(t, err, ErrUnknownNode)
+
+	request.NodeGroup.RequiredAmountOfSignatures = bigInt(1)
+	submission, err := client.Collect(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, 1, submission.Signed)
+
+	request.NodeGroup.Addrs[0], request.NodeGroup.Addrs[1] = request.NodeGroup.Addrs[1], request.NodeGroup.Addrs[0]
+	_, err = client.Collect(ctx, request)
+	assert.ErrorIs(t, err, ErrInvalidNodeGroup)
+
+	request.NodeGroup.Addrs = nil
+	_, err = client.Collect(ctx, request)
+	assert.ErrorIs(t, err, ErrInvalidNodeGroup)
+
+	request.NodeGroup = sortedGroup(1, node.Address())
+	request.Index = nil
+	_, err = client.Collect(ctx, request)
+	assert.ErrorIs(t, err, kzgsdk.ErrUint256Overflow)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	request.Index = bigInt(0)
+	_, err = client.Collect(cancelled, request)
+	assert.ErrorIs(t, err, ErrNotEnoughSignatures)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// only broadcast nodes count
+	request.IsNodeBroadcast = broadcastNodes()
+	_, err = client.Collect(ctx, request)
+	assert.ErrorIs(t, err, ErrNotEnoughSignatures)
+	assert.ErrorIs(t, err, ErrNotBroadcastNode)
+	request.IsNodeBroadcast = nil
+	_, err = client.Collect(ctx, request)
+	assert.Error(t, err)
+}
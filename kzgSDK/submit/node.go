@@ -0,0 +1,60 @@
+// Package submit implements the user side of CommitmentManager.submitCommitment: collecting the
+// signatures of the broadcast nodes of a node group and assembling the transaction.
+package submit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrCommitmentMismatch is returned by a broadcast node asked to sign a commitment that is not
+// the commitment of the data.
+var ErrCommitmentMismatch = errors.New("commitment does not match the data")
+
+// SignRequest is what a user sends to a broadcast node: the data and what the node signs for it.
+type SignRequest struct {
+	Data          []byte
+	SignatureData kzgsdk.SignatureData
+}
+
+// Node is a broadcast node signing commitments, typically the client of its RPC endpoint.
+type Node interface {
+	// Sign returns the 65-byte signature of request.SignatureData, see kzgsdk.SignCommitment.
+	Sign(ctx context.Context, request *SignRequest) ([]byte, error)
+}
+
+// LocalNode is a Node holding the key of a broadcast node. It checks the commitment of the data
+// before signing it, as a broadcast node does.
+type LocalNode struct {
+	sdk *kzgsdk.DomiconSdk
+	key *ecdsa.PrivateKey
+}
+
+// NewLocalNode returns a LocalNode signing with key.
+func NewLocalNode(sdk *kzgsdk.DomiconSdk, key *ecdsa.PrivateKey) *LocalNode {
+	return &LocalNode{sdk: sdk, key: key}
+}
+
+// Address returns the address of the node, the one registered in the NodeManager.
+func (n *LocalNode) Address() common.Address {
+	return crypto.PubkeyToAddress(n.key.PublicKey)
+}
+
+func (n *LocalNode) Sign(ctx context.Context, request *SignRequest) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	commitment, err := n.sdk.CommitData(request.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !commitment.Equal(&request.SignatureData.Commitment) {
+		return nil, ErrCommitmentMismatch
+	}
+	return kzgsdk.SignCommitment(n.key, &request.SignatureData)
+}
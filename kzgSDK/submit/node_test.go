@@ -0,0 +1,47 @@
+package submit
+
+import (
+	"context"
+	"testing"
+
+	kzgsdk "github.com/domicon-labs/dataAuditingkzg-sdk"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSdk(t *testing.T) *kzgsdk.DomiconSdk {
+	sdk, err := kzgsdk.NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	return sdk
+}
+
+func newLocalNode(t *testing.T, sdk *kzgsdk.DomiconSdk) *LocalNode {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return NewLocalNode(sdk, key)
+}
+
+func TestLocalNode(t *testing.T) {
+	ctx := context.Background()
+	sdk := newTestSdk(t)
+	node := newLocalNode(t, sdk)
+	request := &SignRequest{Data: []byte("some blob")}
+	request.SignatureData.Length = bigInt(9)
+	request.SignatureData.Index = bigInt(0)
+	request.SignatureData.Timeout = bigInt(100)
+
+	_, err := node.Sign(ctx, request)
+	assert.ErrorIs(t, err, ErrCommitmentMismatch)
+
+	request.SignatureData.Commitment, err = sdk.CommitData(request.Data)
+	require.NoError(t, err)
+	signature, err := node.Sign(ctx, request)
+	require.NoError(t, err)
+	assert.NoError(t, kzgsdk.VerifyCommitmentSignature(node.Address(), &request.SignatureData, signature))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = node.Sign(cancelled, request)
+	assert.ErrorIs(t, err, context.Canceled)
+}
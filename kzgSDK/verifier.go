@@ -0,0 +1,87 @@
+package kzgsdk
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ErrVerifierRevert is returned when a call to Verifier.verify with the same arguments would revert.
+var ErrVerifierRevert = errors.New("verifier call reverts")
+
+// VerifyLikeContract mirrors Verifier.verify of src/kzg/Verifier.sol with the constants it is
+// deployed with, see DeployedVerifyingKey. The points are the uint256 coordinates of a
+// Pairing.G1Point, (0, 0) being the point at infinity.
+//
+// Unlike kzg.Verify, which works on reduced field elements, the contract requires every argument
+// to be below BABYJUB_P, the scalar field modulus, even the point coordinates, which live in the
+// larger base field; and its precompiles reject points that are not on the curve. Both cases
+// return ErrVerifierRevert. Otherwise VerifyLikeContract returns the result of the pairing check
+//
+//	e(index·proof + commitment - value·G1, G2) · e(-proof, SRS_G2_1) == 1
+func VerifyLikeContract(commitment [2]*big.Int, proof [2]*big.Int, index *big.Int, value *big.Int) (bool, error) {
+	return VerifyLikeContractWithKey(commitment, proof, index, value, DeployedVerifyingKey())
+}
+
+// VerifyLikeContractWithKey is VerifyLikeContract for a Verifier deployed with the constants of vk,
+// as written by WriteSolidityConstants: SRS_G1_0 is vk.G1, g2Generator vk.G2[0] and SRS_G2_1 vk.G2[1].
+func VerifyLikeContractWithKey(commitment [2]*big.Int, proof [2]*big.Int, index *big.Int, value *big.Int, vk kzg.VerifyingKey) (bool, error) {
+	for _, arg := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"_commitment.X", commitment[0]},
+		{"_commitment.Y", commitment[1]},
+		{"_proof.X", proof[0]},
+		{"_proof.Y", proof[1]},
+		{"_index", index},
+		{"_value", value},
+	} {
+		if arg.value == nil || arg.value.Sign() < 0 || arg.value.Cmp(fr.Modulus()) >= 0 {
+			return false, fmt.Errorf("%w: Verifier.verifyKZG: %s is out of range", ErrVerifierRevert, arg.name)
+		}
+	}
+
+	// commitment - value·SRS_G1_0, Pairing.plus fails on a commitment outside of the curve
+	c, ok := g1FromUint256(commitment)
+	if !ok {
+		return false, fmt.Errorf("%w: pairing-add-failed", ErrVerifierRevert)
+	}
+	var commitmentMinusA bn254.G1Affine
+	commitmentMinusA.ScalarMultiplication(&vk.G1, value)
+	commitmentMinusA.Neg(&commitmentMinusA)
+	commitmentMinusA.Add(&commitmentMinusA, &c)
+
+	// index·proof, Pairing.mulScalar fails on a proof outside of the curve
+	h, ok := g1FromUint256(proof)
+	if !ok {
+		return false, fmt.Errorf("%w: pairing-mul-failed", ErrVerifierRevert)
+	}
+	var negProof, lhs bn254.G1Affine
+	negProof.Neg(&h)
+	lhs.ScalarMultiplication(&h, index)
+	lhs.Add(&lhs, &commitmentMinusA)
+
+	valid, err := bn254.PairingCheck([]bn254.G1Affine{lhs, negProof}, []bn254.G2Affine{vk.G2[0], vk.G2[1]})
+	if err != nil {
+		return false, fmt.Errorf("%w: pairing-opcode-failed", ErrVerifierRevert)
+	}
+	return valid, nil
+}
+
+// g1FromUint256 decodes the coordinates of a point as the EVM precompiles do, reporting whether
+// they are those of a point of G1. The coordinates must already be below the scalar field modulus.
+func g1FromUint256(p [2]*big.Int) (bn254.G1Affine, bool) {
+	var point bn254.G1Affine
+	if p[0].Cmp(fp.Modulus()) >= 0 || p[1].Cmp(fp.Modulus()) >= 0 {
+		return point, false
+	}
+	point.X.SetBigInt(p[0])
+	point.Y.SetBigInt(p[1])
+	return point, point.IsInfinity() || point.IsOnCurve()
+}
@@ -0,0 +1,172 @@
+package kzgsdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uint256Point(p *bn254.G1Affine) [2]*big.Int {
+	return [2]*big.Int{p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int))}
+}
+
+// verifyBoth runs kzg.Verify and VerifyLikeContractWithKey on the same, in-range, arguments.
+func verifyBoth(t *testing.T, vk kzg.VerifyingKey, commitment kzg.Digest, proof kzg.OpeningProof, point fr.Element) (bool, bool) {
+	expected := kzg.Verify(&commitment, &proof, point, vk) == nil
+	valid, err := VerifyLikeContractWithKey(uint256Point(&commitment), uint256Point(&proof.H), point.BigInt(new(big.Int)), proof.ClaimedValue.BigInt(new(big.Int)), vk)
+	require.NoError(t, err)
+	return expected, valid
+}
+
+func TestVerifyLikeContract(t *testing.T) {
+	sdk, err := NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	vk := sdk.SRS().Vk
+
+	var one fr.Element
+	one.SetOne()
+	for i := 0; i < 8; i++ {
+		polynomial := randomPolynomial(2 + 15*i)
+		commitment, err := sdk.Commit(polynomial)
+		require.NoError(t, err)
+		var point fr.Element
+		point.SetRandom()
+		proof, err := kzg.Open(polynomial, point, sdk.SRS().Pk)
+		require.NoError(t, err)
+
+		expected, valid := verifyBoth(t, vk, commitment, proof, point)
+		assert.True(t, expected)
+		assert.True(t, valid)
+
+		wrongValue := proof
+		wrongValue.ClaimedValue.Add(&wrongValue.ClaimedValue, &one)
+		expected, valid = verifyBoth(t, vk, commitment, wrongValue, point)
+		assert.False(t, expected)
+		assert.False(t, valid)
+
+		var wrongPoint fr.Element
+		wrongPoint.Add(&point, &one)
+		expected, valid = verifyBoth(t, vk, commitment, proof, wrongPoint)
+		assert.False(t, expected)
+		assert.False(t, valid)
+
+		wrongProof := proof
+		wrongProof.H.Add(&wrongProof.H, &commitment)
+		expected, valid = verifyBoth(t, vk, commitment, wrongProof, point)
+		assert.False(t, expected)
+		assert.False(t, valid)
+	}
+
+	// a constant polynomial opens to the point at infinity
+	commitment, err := sdk.Commit([]fr.Element{one, {}})
+	require.NoError(t, err)
+	proof, err := kzg.Open([]fr.Element{one, {}}, one, sdk.SRS().Pk)
+	require.NoError(t, err)
+	assert.True(t, proof.H.IsInfinity())
+	expected, valid := verifyBoth(t, vk, commitment, proof, one)
+	assert.True(t, expected)
+	assert.True(t, valid)
+}
+
+func TestVerifyLikeContractReverts(t *testing.T) {
+	sdk, err := NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	vk := sdk.SRS().Vk
+
+	polynomial := randomPolynomial(10)
+	commitment, err := sdk.Commit(polynomial)
+	require.NoError(t, err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := kzg.Open(polynomial, point, sdk.SRS().Pk)
+	require.NoError(t, err)
+	c, h := uint256Point(&commitment), uint256Point(&proof.H)
+	index, value := point.BigInt(new(big.Int)), proof.ClaimedValue.BigInt(new(big.Int))
+
+	// kzg.Verify takes reduced field elements: index + r and value + r are the same elements,
+	// the contract rejects them
+	unreducedIndex := new(big.Int).Add(index, fr.Modulus())
+	unreducedValue := new(big.Int).Add(value, fr.Modulus())
+	var reduced fr.Element
+	reduced.SetBigInt(unreducedIndex)
+	assert.NoError(t, kzg.Verify(&commitment, &proof, reduced, vk))
+
+	offCurve := [2]*big.Int{big.NewInt(1), big.NewInt(1)}
+	// a coordinate between the scalar and base field moduli is a valid coordinate, but not for the contract
+	aboveScalar := [2]*big.Int{new(big.Int).Sub(fp.Modulus(), big.NewInt(1)), big.NewInt(0)}
+
+	for _, tt := range []struct {
+		name       string
+		commitment [2]*big.Int
+		proof      [2]*big.Int
+		index      *big.Int
+		value      *big.Int
+	}{
+		{"index out of range", c, h, unreducedIndex, value},
+		{"value out of range", c, h, index, unreducedValue},
+		{"commitment out of range", aboveScalar, h, index, value},
+		{"proof out of range", c, [2]*big.Int{h[0], fr.Modulus()}, index, value},
+		{"negative index", c, h, big.NewInt(-1), value},
+		{"missing value", c, h, index, nil},
+		{"commitment off curve", offCurve, h, index, value},
+		{"proof off curve", c, offCurve, index, value},
+	} {
+		valid, err := VerifyLikeContractWithKey(tt.commitment, tt.proof, tt.index, tt.value, vk)
+		assert.ErrorIs(t, err, ErrVerifierRevert, tt.name)
+		assert.False(t, valid, tt.name)
+	}
+
+	valid, err := VerifyLikeContractWithKey(c, h, index, value, vk)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	// the reverts do not depend on the key
+	_, err = VerifyLikeContract(c, h, unreducedIndex, value)
+	assert.ErrorIs(t, err, ErrVerifierRevert)
+}
+
+func TestVerifyLikeContractDeployed(t *testing.T) {
+	sdk, err := NewDomiconSdkFromSol()
+	require.NoError(t, err)
+	deployed := DeployedVerifyingKey()
+
+	// the deployed Verifier rejects the honest proofs of the SDK setup, as kzg.Verify does with its key
+	for i := 0; i < 4; i++ {
+		polynomial := randomPolynomial(2 + 30*i)
+		commitment, err := sdk.Commit(polynomial)
+		require.NoError(t, err)
+		var point fr.Element
+		point.SetRandom()
+		proof, err := kzg.Open(polynomial, point, sdk.SRS().Pk)
+		require.NoError(t, err)
+		require.NoError(t, sdk.Verify(&commitment, &proof, point))
+
+		expected, valid := verifyBoth(t, deployed, commitment, proof, point)
+		assert.False(t, expected)
+		assert.False(t, valid)
+		valid, err = VerifyLikeContract(uint256Point(&commitment), uint256Point(&proof.H), point.BigInt(new(big.Int)), proof.ClaimedValue.BigInt(new(big.Int)))
+		require.NoError(t, err)
+		assert.False(t, valid)
+	}
+
+	// the opening of a constant polynomial does not depend on SRS_G2_1
+	constant := randomPolynomial(2)
+	constant[1].SetZero()
+	commitment, err := sdk.Commit(constant)
+	require.NoError(t, err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := kzg.Open(constant, point, sdk.SRS().Pk)
+	require.NoError(t, err)
+	expected, valid := verifyBoth(t, deployed, commitment, proof, point)
+	assert.True(t, expected)
+	assert.True(t, valid)
+	valid, err = VerifyLikeContract(uint256Point(&commitment), uint256Point(&proof.H), point.BigInt(new(big.Int)), proof.ClaimedValue.BigInt(new(big.Int)))
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+pragma solidity 0.8.15;
+
+import { NodeInfo } from "src/NodeManager.sol";
+import { NodeGroup, NameSpace } from "src/StorageManager.sol";
+import { CommonTest } from "test/setup/CommonTest.sol";
+
+/// @dev The vectors of test/fixtures/storage-keys.json are shared with kzgSDK/contracts/keys_test.go.
+contract StorageManagerTest is CommonTest {
+    string vectors;
+    address creator;
+
+    function setUp() public virtual override {
+        super.setUp();
+        vectors = vm.readFile(string.concat(vm.projectRoot(), "/test/fixtures/storage-keys.json"));
+        creator = vm.parseJsonAddress(vectors, ".creator");
+    }
+
+    function _vector(uint256 _i, string memory _field) internal pure returns (string memory) {
+        return string.concat(".vectors[", vm.toString(_i), "].", _field);
+    }
+
+    function _registerNode(address _addr, bool _broadcast) internal {
+        NodeInfo memory nodeInfo = NodeInfo({
+            url: "url",
+            name: "name",
+            stakedTokens: 100,
+            location: "cn",
+            maxStorageSpace: 1000,
+            addr: _addr
+        });
+        vm.prank(_addr);
+        if (_broadcast) {
+            nodeManager.registerBroadcastNode(nodeInfo);
+        } else {
+            nodeManager.registerStorageNode(nodeInfo);
+        }
+    }
+
+    function testKeyVectors() public {
+        for (uint256 i = 0; i < 4; i++) {
+            address[] memory addresses = vm.parseJsonAddressArray(vectors, _vector(i, "addresses"));
+            address[] memory sorted = vm.parseJsonAddressArray(vectors, _vector(i, "sorted"));
+            uint256 required = vm.parseJsonUint(vectors, _vector(i, "required"));
+
+            assertEq(storageManager.sortAddresses(addresses), sorted);
+            assertEq(
+                storageManager.getNodeGroupKey(sorted, required), vm.parseJsonBytes32(vectors, _vector(i, "nodeGroupKey"))
+            );
+            vm.prank(creator);
+            assertEq(storageManager.getNameSpaceKey(sorted), vm.parseJsonBytes32(vectors, _vector(i, "nameSpaceKey")));
+        }
+    }
+
+    function testRegisterNodeGroupKey() public {
+        address[] memory addresses = vm.parseJsonAddressArray(vectors, _vector(1, "addresses"));
+        for (uint256 i = 0; i < addresses.length; i++) {
+            _registerNode(addresses[i], true);
+        }
+
+        vm.prank(alice);
+        bytes32 key = storageManager.registerNodeGroup(2, addresses);
+        assertEq(key, vm.parseJsonBytes32(vectors, _vector(1, "nodeGroupKey")));
+        NodeGroup memory info = storageManager.NODEGROUP(key);
+        assertEq(info.addrs, vm.parseJsonAddressArray(vectors, _vector(1, "sorted")));
+    }
+
+    function testRegisterNameSpaceKey() public {
+        address[] memory addresses = vm.parseJsonAddressArray(vectors, _vector(3, "addresses"));
+        for (uint256 i = 0; i < addresses.length; i++) {
+            if (!nodeManager.isNodeStorage(addresses[i])) {
+                _registerNode(addresses[i], false);
+            }
+        }
+
+        vm.prank(creator);
+        bytes32 key = storageManager.registerNameSpace(addresses);
+        assertEq(key, vm.parseJsonBytes32(vectors, _vector(3, "nameSpaceKey")));
+        NameSpace memory nameSpace = storageManager.NAMESPACE(key);
+        assertEq(nameSpace.creator, creator);
+        assertEq(nameSpace.addr, vm.parseJsonAddressArray(vectors, _vector(3, "sorted")));
+    }
+}